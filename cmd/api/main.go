@@ -1,7 +1,14 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	_ "github.com/f00b455/golang-template/docs" // Import generated docs
 	"github.com/f00b455/golang-template/internal/config"
@@ -27,17 +34,56 @@ import (
 // @host      localhost:3002
 // @BasePath  /api
 
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+
+// shutdownTimeout bounds how long in-flight requests may take during shutdown.
+const shutdownTimeout = 10 * time.Second
+
 func main() {
 	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Fatal("Invalid configuration: ", err)
+	}
 
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	rssHandler := handlers.NewRSSHandler(handlers.WithWarmCache())
+	rssHandler.StartAutoRefresh(ctx, cfg.RSSAutoRefreshInterval)
+
+	server := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           newRouter(cfg, rssHandler),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Printf("Server starting on port %s", cfg.Port)
+	log.Printf("Terminal frontend available at http://localhost:%s/", cfg.Port)
+	log.Printf("Swagger documentation available at http://localhost:%s/documentation/index.html", cfg.Port)
+
+	if err := run(ctx, server); err != nil {
+		log.Fatal("Failed to start server:", err)
+	}
+	log.Println("Server stopped")
+}
+
+// newRouter builds the gin engine with all middleware and routes registered.
+func newRouter(cfg *config.Config, rssHandler *handlers.RSSHandler) *gin.Engine {
 	router := gin.New()
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
-	router.Use(middleware.CORS())
+	router.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowedOrigins: cfg.CORSAllowedOrigins,
+		AllowedMethods: cfg.CORSAllowedMethods,
+		AllowedHeaders: cfg.CORSAllowedHeaders,
+	}))
+	router.Use(middleware.LimitQueryLength(cfg.MaxQueryLength))
 
 	// API routes
 	api := router.Group("/api")
@@ -45,12 +91,32 @@ func main() {
 		// Greet endpoints
 		greetHandler := handlers.NewGreetHandler()
 		api.GET("/greet", greetHandler.Greet)
+		api.POST("/greet", greetHandler.GreetPost)
+		api.POST("/greet/batch", greetHandler.GreetBatch)
 
 		// RSS endpoints
-		rssHandler := handlers.NewRSSHandler()
 		api.GET("/rss/spiegel/latest", rssHandler.GetLatest)
 		api.GET("/rss/spiegel/top5", rssHandler.GetTop5)
 		api.GET("/rss/spiegel/export", rssHandler.ExportHeadlines)
+		api.GET("/rss/spiegel/random", rssHandler.GetRandom)
+		api.GET("/rss/sources", rssHandler.GetSources)
+		api.GET("/rss/:source/top5", rssHandler.GetSourceTop5)
+		api.GET("/rss/:source/search", rssHandler.Search)
+		api.GET("/rss/:source/categories", rssHandler.GetCategories)
+		api.GET("/rss/:source/recent", rssHandler.GetRecent)
+		api.GET("/rss/:source/cache", rssHandler.GetCacheStatus)
+		api.GET("/proxy", rssHandler.ProxyFeed)
+
+		// Source management endpoints
+		api.GET("/sources/export.opml", rssHandler.ExportSources)
+
+		// Admin endpoints are only exposed when an admin token is configured
+		if cfg.AdminToken != "" {
+			admin := api.Group("/admin", middleware.AdminAuth(cfg.AdminToken))
+			admin.GET("/compare-live", rssHandler.CompareLive)
+			api.POST("/rss/:source/refresh", middleware.AdminAuth(cfg.AdminToken), rssHandler.RefreshSource)
+			api.POST("/sources/import", middleware.AdminAuth(cfg.AdminToken), rssHandler.ImportSources)
+		}
 	}
 
 	// Static files for terminal frontend
@@ -61,11 +127,29 @@ func main() {
 	// Swagger documentation
 	router.GET("/documentation/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	log.Printf("Server starting on port %s", cfg.Port)
-	log.Printf("Terminal frontend available at http://localhost:%s/", cfg.Port)
-	log.Printf("Swagger documentation available at http://localhost:%s/documentation/index.html", cfg.Port)
+	return router
+}
 
-	if err := router.Run(":" + cfg.Port); err != nil {
-		log.Fatal("Failed to start server:", err)
+// run serves until ctx is cancelled, then shuts the server down gracefully
+// so in-flight requests can finish within shutdownTimeout.
+func run(ctx context.Context, server *http.Server) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+		}
+		close(serveErr)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
 	}
+
+	log.Println("Shutting down server...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	return server.Shutdown(shutdownCtx)
 }
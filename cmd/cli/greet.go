@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/f00b455/golang-template/pkg/core"
+	"github.com/spf13/cobra"
+)
+
+// greetCmd greets every positional argument in turn, e.g. `hello-cli greet Alice Bob`.
+var greetCmd = &cobra.Command{
+	Use:   "greet NAME [NAME...]",
+	Short: "Greet one or more names",
+	Long:  `Prints a boxed greeting for each name given as a positional argument.`,
+	Args:  validateGreetArgs,
+	Run:   runGreetCommand,
+}
+
+func init() {
+	rootCmd.AddCommand(greetCmd)
+}
+
+// validateGreetArgs requires at least one name and rejects names that are too long.
+func validateGreetArgs(cmd *cobra.Command, args []string) error {
+	if err := cobra.MinimumNArgs(1)(cmd, args); err != nil {
+		return err
+	}
+	for _, arg := range args {
+		if err := core.ValidateName(arg, core.DefaultMaxNameLength); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runGreetCommand(cmd *cobra.Command, args []string) {
+	out := cmd.OutOrStdout()
+	for _, arg := range args {
+		displayGreeting(out, arg)
+		fmt.Fprintln(out)
+	}
+}
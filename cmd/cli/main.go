@@ -1,8 +1,11 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/f00b455/golang-template/pkg/core"
@@ -12,57 +15,116 @@ import (
 	"github.com/theckman/yacspin"
 )
 
+const (
+	minRepeat = 1
+	maxRepeat = 100
+)
+
 var (
-	name string
+	name         string
+	jsonOutput   bool
+	noColor      bool
+	repeat       int
+	prefix       string
+	suffix       string
+	lang         string
+	timeGreeting bool
 )
 
+// greetingOutput is the machine-readable form of the greeting printed with --json.
+type greetingOutput struct {
+	Greeting string `json:"greeting"`
+	Name     string `json:"name"`
+}
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "hello-cli",
 	Short: "A colorful Hello-World CLI application",
 	Long:  `A colorful Hello-World CLI application built with Go and Cobra.`,
-	Run:   runHelloCommand,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		configureColor()
+	},
+	PreRunE: validateRootFlags,
+	Run:     runHelloCommand,
 }
 
 func main() {
+	// Commands report their own errors (Cobra prints them unless silenced)
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
 		os.Exit(1)
 	}
 }
 
 func init() {
 	rootCmd.Flags().StringVar(&name, "name", "World", "Name to greet")
+	rootCmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the greeting as JSON without animations or colors")
+	rootCmd.Flags().IntVar(&repeat, "repeat", minRepeat, fmt.Sprintf("Number of times to print the greeting (%d-%d)", minRepeat, maxRepeat))
+	rootCmd.PersistentFlags().StringVar(&prefix, "prefix", "✨", "Decoration printed before the greeting (empty for none)")
+	rootCmd.PersistentFlags().StringVar(&suffix, "suffix", "✨", "Decoration printed after the greeting (empty for none)")
+	rootCmd.PersistentFlags().StringVar(&lang, "lang", "en", "Greeting language (en, de, fr, es)")
+	rootCmd.PersistentFlags().BoolVar(&timeGreeting, "time-greeting", false, "Greet by time of day (Good morning, ...) instead of Hello")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output (also enabled by the NO_COLOR env var)")
+}
+
+// validateRootFlags rejects flag values before any output is produced.
+func validateRootFlags(cmd *cobra.Command, args []string) error {
+	if repeat < minRepeat || repeat > maxRepeat {
+		return fmt.Errorf("--repeat must be between %d and %d, got %d", minRepeat, maxRepeat, repeat)
+	}
+	if err := core.ValidateName(name, core.DefaultMaxNameLength); err != nil {
+		return fmt.Errorf("--name: %w", err)
+	}
+	return nil
+}
+
+// configureColor disables all fatih/color output when --no-color or NO_COLOR is set.
+func configureColor() {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		color.NoColor = true
+	}
 }
 
 func runHelloCommand(cmd *cobra.Command, args []string) {
+	if jsonOutput {
+		if err := printGreetingJSON(cmd.OutOrStdout(), name); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	out := cmd.OutOrStdout()
+
 	// Welcome message
 	magenta := color.New(color.FgMagenta).SprintFunc()
 	green := color.New(color.FgGreen).SprintFunc()
 	red := color.New(color.FgRed).SprintFunc()
 
-	fmt.Printf("%s\n\n", magenta("🎉 Welcome to Hello CLI!"))
+	fmt.Fprintf(out, "%s\n\n", magenta("🎉 Welcome to Hello CLI!"))
 
 	// Run spinner
-	if err := runSpinner(); err != nil {
-		fmt.Printf("%s\n", red(fmt.Sprintf("❌ Error: %v", err)))
+	if err := runSpinner(out); err != nil {
+		fmt.Fprintf(out, "%s\n", red(fmt.Sprintf("❌ Error: %v", err)))
 		os.Exit(1)
 	}
 
 	// Run progress bar
-	if err := runProgressBar(); err != nil {
-		fmt.Printf("%s\n", red(fmt.Sprintf("❌ Error: %v", err)))
+	if err := runProgressBar(out); err != nil {
+		fmt.Fprintf(out, "%s\n", red(fmt.Sprintf("❌ Error: %v", err)))
 		os.Exit(1)
 	}
 
 	// Display greeting
-	fmt.Println()
-	displayGreeting(name)
+	for i := 0; i < repeat; i++ {
+		fmt.Fprintln(out)
+		displayGreeting(out, name)
+	}
 
-	fmt.Printf("%s\n", green("✅ All done! Have a great day!"))
+	fmt.Fprintf(out, "%s\n", green("✅ All done! Have a great day!"))
 }
 
-func runSpinner() error {
+func runSpinner(out io.Writer) error {
 	// Use shorter delay for testing
 	spinnerDelay := 5 * time.Second
 	if os.Getenv("GO_ENV") == "test" {
@@ -70,10 +132,15 @@ func runSpinner() error {
 	}
 
 	cfg := yacspin.Config{
+		Writer:    out,
 		Frequency: 100 * time.Millisecond,
 		CharSet:   yacspin.CharSets[14], // Dots spinner
 		Message:   " Preparing something awesome...",
 	}
+	if color.NoColor {
+		// Plain mode: no cursor control sequences, one line per update
+		cfg.TerminalMode = yacspin.ForceNoTTYMode | yacspin.ForceDumbTerminalMode
+	}
 
 	spinner, err := yacspin.New(cfg)
 	if err != nil {
@@ -91,11 +158,11 @@ func runSpinner() error {
 		return err
 	}
 
-	fmt.Println("Spinner completed: Ready!")
+	fmt.Fprintln(out, "Spinner completed: Ready!")
 	return nil
 }
 
-func runProgressBar() error {
+func runProgressBar(out io.Writer) error {
 	// Use shorter delay for testing
 	progressDelay := 30 * time.Millisecond
 	if os.Getenv("GO_ENV") == "test" {
@@ -103,6 +170,7 @@ func runProgressBar() error {
 	}
 
 	bar := progressbar.NewOptions(100,
+		progressbar.OptionSetWriter(out),
 		progressbar.OptionSetDescription("Progress"),
 		progressbar.OptionShowCount(),
 		progressbar.OptionSetWidth(50),
@@ -119,17 +187,43 @@ func runProgressBar() error {
 		time.Sleep(progressDelay)
 	}
 
-	fmt.Println("\nProgress completed: 100%")
+	fmt.Fprintln(out, "\nProgress completed: 100%")
 	return nil
 }
 
-func displayGreeting(name string) {
-	config := core.FooConfig{
-		Prefix: "✨",
-		Suffix: "✨",
+// greetingConfig builds the greeting from --lang, --prefix and --suffix,
+// separating each non-empty decoration from the message by a space.
+func greetingConfig() core.FooConfig {
+	cfg := core.FooConfig{Lang: lang}
+	if prefix != "" {
+		cfg.Prefix = prefix + " "
 	}
+	if suffix != "" {
+		cfg.Suffix = " " + suffix
+	}
+	return cfg
+}
 
-	greetingMessage := core.FooGreet(config, name)
+// greetingText returns the greeting for name, by time of day when --time-greeting is set.
+func greetingText(name string) string {
+	if timeGreeting {
+		return core.FooGreetAt(greetingConfig(), name, time.Now())
+	}
+	return core.FooGreet(greetingConfig(), name)
+}
+
+// printGreetingJSON writes the plain greeting as a single JSON object for scripting.
+func printGreetingJSON(w io.Writer, name string) error {
+	return json.NewEncoder(w).Encode(greetingOutput{
+		Greeting: greetingText(name),
+		Name:     name,
+	})
+}
+
+// displayGreeting prints the boxed greeting from core, indented and with a rainbow-colored message.
+func displayGreeting(out io.Writer, name string) {
+	greetingMessage := greetingText(name)
+	box := core.FormatBox(greetingMessage)
 
 	// Create colorful text (simplified gradient effect)
 	cyan := color.New(color.FgCyan, color.Bold).SprintFunc()
@@ -143,14 +237,11 @@ func displayGreeting(name string) {
 		colorFunc := colors[i%len(colors)]
 		coloredMessage += colorFunc(string(char))
 	}
+	box = strings.Replace(box, greetingMessage, coloredMessage, 1)
 
-	// Create boxed output (simplified)
-	boxWidth := len(greetingMessage) + 4
-
-	fmt.Printf("    ╔%s╗\n", fmt.Sprintf("%*s", boxWidth-2, ""))
-	fmt.Printf("    ║%s║\n", fmt.Sprintf("%*s", boxWidth-2, "Hello CLI"))
-	fmt.Printf("    ╠%s╣\n", fmt.Sprintf("%*s", boxWidth-2, ""))
-	fmt.Printf("    ║ %s ║\n", coloredMessage)
-	fmt.Printf("    ║%s║\n", fmt.Sprintf("%*s", boxWidth-2, ""))
-	fmt.Printf("    ╚%s╝\n", fmt.Sprintf("%*s", boxWidth-2, ""))
+	for _, line := range strings.SplitAfter(box, "\n") {
+		if line != "" {
+			fmt.Fprintf(out, "    %s", line)
+		}
+	}
 }
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/f00b455/golang-template/internal/handlers"
+	"github.com/f00b455/golang-template/pkg/shared"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// executeCommand runs rootCmd with args and returns the captured command output.
+func executeCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&out)
+	rootCmd.SetArgs(args)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		rootCmd.SetArgs(nil)
+		resetFlags(rootCmd)
+	})
+
+	err := rootCmd.Execute()
+	return out.String(), err
+}
+
+// resetFlags restores every flag to its default so tests don't leak state through package globals.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
+func TestRootCommand_JSONOutput(t *testing.T) {
+	output, err := executeCommand(t, "--json", "--name", "Alice")
+	require.NoError(t, err)
+
+	var result greetingOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, "✨ Hello, Alice! ✨", result.Greeting)
+	assert.Equal(t, "Alice", result.Name)
+	assert.NotContains(t, output, "\x1b[")
+	assert.NotContains(t, output, "╔")
+}
+
+func TestRootCommand_JSONOutputDefaultName(t *testing.T) {
+	output, err := executeCommand(t, "--json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"greeting":"✨ Hello, World! ✨","name":"World"}`, output)
+}
+
+// forceColor simulates an interactive terminal so color output would normally be emitted.
+func forceColor(t *testing.T) {
+	t.Helper()
+	t.Setenv("GO_ENV", "test")
+	original := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() { color.NoColor = original })
+}
+
+func TestRootCommand_ColorEnabledByDefault(t *testing.T) {
+	forceColor(t)
+	t.Setenv("NO_COLOR", "")
+
+	output, err := executeCommand(t)
+	require.NoError(t, err)
+	assert.Contains(t, output, "\x1b[")
+}
+
+func TestRootCommand_NoColorEnv(t *testing.T) {
+	forceColor(t)
+	t.Setenv("NO_COLOR", "1")
+
+	output, err := executeCommand(t, "--name", "CI")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Hello, CI!")
+	assert.NotContains(t, output, "\x1b[")
+}
+
+func TestRootCommand_NoColorFlag(t *testing.T) {
+	forceColor(t)
+	t.Setenv("NO_COLOR", "")
+
+	output, err := executeCommand(t, "--no-color")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Progress completed: 100%")
+	assert.NotContains(t, output, "\x1b[")
+}
+
+func TestRootCommand_Repeat(t *testing.T) {
+	t.Setenv("GO_ENV", "test")
+
+	output, err := executeCommand(t, "--repeat", "3", "--name", "Bob")
+	require.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(output, "Hello CLI║"))
+	assert.Equal(t, 3, strings.Count(output, "Hello, Bob!"))
+	assert.Equal(t, 3, strings.Count(output, "╚"))
+}
+
+func TestRootCommand_CustomPrefixSuffix(t *testing.T) {
+	output, err := executeCommand(t, "--json", "--prefix", ">>", "--suffix", "<<")
+	require.NoError(t, err)
+
+	var result greetingOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, ">> Hello, World! <<", result.Greeting)
+}
+
+func TestRootCommand_EmptyPrefixSuffix(t *testing.T) {
+	output, err := executeCommand(t, "--json", "--prefix", "", "--suffix", "")
+	require.NoError(t, err)
+
+	var result greetingOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, "Hello, World!", result.Greeting)
+}
+
+func TestRootCommand_Lang(t *testing.T) {
+	output, err := executeCommand(t, "--json", "--lang", "de", "--name", "Jörg")
+	require.NoError(t, err)
+
+	var result greetingOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, "✨ Hallo, Jörg! ✨", result.Greeting)
+}
+
+func TestRootCommand_TimeGreeting(t *testing.T) {
+	output, err := executeCommand(t, "--json", "--time-greeting", "--name", "Alice")
+	require.NoError(t, err)
+
+	var result greetingOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Regexp(t, `^✨ Good (morning|afternoon|evening|night), Alice! ✨$`, result.Greeting)
+}
+
+func TestGreetCommand_CustomPrefixSuffix(t *testing.T) {
+	forceColor(t)
+
+	output, err := executeCommand(t, "greet", "--no-color", "--prefix", ">>", "--suffix", "<<", "Alice")
+	require.NoError(t, err)
+	assert.Contains(t, output, ">> Hello, Alice! <<")
+}
+
+func TestRootCommand_RepeatOutOfRange(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "zero", value: "0"},
+		{name: "negative", value: "-2"},
+		{name: "above maximum", value: "101"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := executeCommand(t, "--repeat", tt.value)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "--repeat must be between 1 and 100")
+			assert.NotContains(t, output, "Welcome to Hello CLI")
+		})
+	}
+}
+
+func TestRootCommand_NameTooLong(t *testing.T) {
+	output, err := executeCommand(t, "--name", strings.Repeat("a", 101))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--name: name too long (max 100 characters)")
+	assert.NotContains(t, output, "Welcome to Hello CLI")
+}
+
+func TestGreetCommand_GreetsEachName(t *testing.T) {
+	output, err := executeCommand(t, "greet", "Alice", "Bob", "Charlie")
+	require.NoError(t, err)
+
+	for _, name := range []string{"Alice", "Bob", "Charlie"} {
+		assert.Contains(t, output, "✨ Hello, "+name+"! ✨")
+	}
+	assert.Less(t, strings.Index(output, "Alice"), strings.Index(output, "Bob"))
+	assert.Less(t, strings.Index(output, "Bob"), strings.Index(output, "Charlie"))
+	assert.NotContains(t, output, "Progress completed")
+}
+
+func TestGreetCommand_RequiresName(t *testing.T) {
+	_, err := executeCommand(t, "greet")
+	assert.Error(t, err)
+}
+
+func TestGreetCommand_RejectsLongName(t *testing.T) {
+	_, err := executeCommand(t, "greet", "Alice", strings.Repeat("b", 101))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "name too long")
+}
+
+func TestGreetCommand_StripsControlCharacters(t *testing.T) {
+	output, err := executeCommand(t, "greet", "--no-color", "Ali\nce\x1b[2J")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Hello, Alice[2J!")
+	assert.NotContains(t, output, "\x1b")
+}
+
+func TestOpenAPICommand_WritesSpecFile(t *testing.T) {
+	outPath := filepath.Join(t.TempDir(), "openapi.json")
+
+	output, err := executeCommand(t, "openapi", "--out", outPath)
+	require.NoError(t, err)
+	assert.Contains(t, output, outPath)
+
+	data, err := os.ReadFile(outPath)
+	require.NoError(t, err)
+
+	var spec struct {
+		Swagger string                 `json:"swagger"`
+		Paths   map[string]interface{} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(data, &spec))
+
+	assert.Equal(t, "2.0", spec.Swagger)
+	assert.Contains(t, spec.Paths, "/rss/spiegel/latest")
+	assert.Contains(t, spec.Paths, "/rss/spiegel/top5")
+	assert.Contains(t, spec.Paths, "/greet")
+}
+
+func TestOpenAPICommand_WritesToStdout(t *testing.T) {
+	output, err := executeCommand(t, "openapi", "--out", "-")
+	require.NoError(t, err)
+	assert.True(t, json.Valid([]byte(output)))
+}
+
+// newHeadlinesAPI serves a fixed top5 response and records the last request query.
+func newHeadlinesAPI(t *testing.T, query *url.Values) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/rss/spiegel/top5" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		*query = r.URL.Query()
+		_ = json.NewEncoder(w).Encode(handlers.HeadlinesResponse{
+			Headlines: []shared.RssHeadline{
+				{Title: "Erste Meldung", Link: "https://example.com/1"},
+				{Title: "Zweite Meldung", Link: "https://example.com/2"},
+			},
+			TotalCount: 2,
+		})
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("API_URL", server.URL)
+}
+
+func TestRSSCommand_PrintsNumberedList(t *testing.T) {
+	forceColor(t)
+	var query url.Values
+	newHeadlinesAPI(t, &query)
+
+	output, err := executeCommand(t, "rss", "--no-color", "--limit", "2", "--filter", "Meldung")
+	require.NoError(t, err)
+
+	assert.Contains(t, output, " 1. Erste Meldung\n    https://example.com/1\n")
+	assert.Contains(t, output, " 2. Zweite Meldung\n")
+	assert.Equal(t, "2", query.Get("limit"))
+	assert.Equal(t, "Meldung", query.Get("filter"))
+}
+
+func TestRSSCommand_JSONOutput(t *testing.T) {
+	var query url.Values
+	newHeadlinesAPI(t, &query)
+
+	output, err := executeCommand(t, "rss", "--json")
+	require.NoError(t, err)
+
+	var response handlers.HeadlinesResponse
+	require.NoError(t, json.Unmarshal([]byte(output), &response))
+	require.Len(t, response.Headlines, 2)
+	assert.Equal(t, "Erste Meldung", response.Headlines[0].Title)
+}
+
+func TestRSSCommand_Source(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_ = json.NewEncoder(w).Encode(handlers.HeadlinesResponse{
+			Headlines:  []shared.RssHeadline{{Title: "KI-Meldung", Link: "https://example.com/ki"}},
+			TotalCount: 1,
+		})
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("API_URL", server.URL)
+
+	output, err := executeCommand(t, "rss", "--no-color", "--source", "heise-online")
+	require.NoError(t, err)
+	assert.Equal(t, "/api/rss/heise-online/top5", path)
+	assert.Contains(t, output, " 1. KI-Meldung\n")
+}
+
+func TestRSSCommand_UnknownSource(t *testing.T) {
+	var query url.Values
+	newHeadlinesAPI(t, &query)
+
+	output, err := executeCommand(t, "rss", "--source", "heise")
+	require.Error(t, err)
+	assert.Contains(t, output, `unknown source "heise"`)
+}
+
+func TestRSSCommand_NetworkError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	t.Setenv("API_URL", server.URL)
+
+	output, err := executeCommand(t, "rss")
+	require.Error(t, err)
+	assert.Contains(t, output, "❌ Error: failed to reach API")
+}
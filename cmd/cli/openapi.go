@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/f00b455/golang-template/docs"
+	"github.com/spf13/cobra"
+)
+
+const openAPIFileMode = 0o644
+
+var openAPIOut string
+
+// openapiCmd writes the API's Swagger/OpenAPI spec for client generation pipelines.
+var openapiCmd = &cobra.Command{
+	Use:   "openapi",
+	Short: "Write the API's OpenAPI (Swagger) spec to a file",
+	Long:  `Writes the OpenAPI (Swagger 2.0) JSON generated from the API's swag annotations.`,
+	Args:  cobra.NoArgs,
+	RunE:  runOpenAPICommand,
+}
+
+func init() {
+	openapiCmd.Flags().StringVar(&openAPIOut, "out", "openapi.json", "Output file path (use - for stdout)")
+	rootCmd.AddCommand(openapiCmd)
+}
+
+func runOpenAPICommand(cmd *cobra.Command, args []string) error {
+	spec, err := generateOpenAPISpec()
+	if err != nil {
+		return err
+	}
+
+	if openAPIOut == "-" {
+		_, err = cmd.OutOrStdout().Write(spec)
+		return err
+	}
+
+	if err := os.WriteFile(openAPIOut, spec, openAPIFileMode); err != nil {
+		return fmt.Errorf("failed to write spec: %w", err)
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "OpenAPI spec written to %s\n", openAPIOut)
+	return nil
+}
+
+// generateOpenAPISpec renders the registered swag spec as indented JSON.
+func generateOpenAPISpec() ([]byte, error) {
+	var spec map[string]interface{}
+	if err := json.Unmarshal([]byte(docs.SwaggerInfo.ReadDoc()), &spec); err != nil {
+		return nil, fmt.Errorf("invalid generated spec: %w", err)
+	}
+
+	out, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode spec: %w", err)
+	}
+	return append(out, '\n'), nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/f00b455/golang-template/internal/config"
+	"github.com/f00b455/golang-template/internal/handlers"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const rssRequestTimeout = 5 * time.Second
+
+var (
+	rssSource string
+	rssLimit  int
+	rssFilter string
+	rssJSON   bool
+)
+
+// rssCmd prints headlines from a running API server, e.g. `hello-cli rss --source spiegel --limit 5`.
+var rssCmd = &cobra.Command{
+	Use:   "rss",
+	Short: "Print the latest headlines from the API",
+	Long: `Fetches headlines from the running API (set API_URL to override the default
+http://localhost:$PORT) and prints them as a numbered list or as JSON.`,
+	Args:          cobra.NoArgs,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE:          runRSSCommand,
+}
+
+func init() {
+	rssCmd.Flags().StringVar(&rssSource, "source", "spiegel", "Feed source key to read headlines from (see /api/rss/sources)")
+	rssCmd.Flags().IntVar(&rssLimit, "limit", 5, "Number of headlines to print")
+	rssCmd.Flags().StringVar(&rssFilter, "filter", "", "Only print headlines containing this keyword")
+	rssCmd.Flags().BoolVar(&rssJSON, "json", false, "Print the headlines as JSON")
+	rootCmd.AddCommand(rssCmd)
+}
+
+func runRSSCommand(cmd *cobra.Command, args []string) error {
+	response, err := fetchRSSHeadlines(apiBaseURL(), rssSource, rssLimit, rssFilter)
+	if err != nil {
+		red := color.New(color.FgRed).SprintFunc()
+		fmt.Fprintln(cmd.ErrOrStderr(), red(fmt.Sprintf("❌ Error: %v", err)))
+		return err
+	}
+
+	if rssJSON {
+		return json.NewEncoder(cmd.OutOrStdout()).Encode(response)
+	}
+	printHeadlineList(cmd.OutOrStdout(), response)
+	return nil
+}
+
+// apiBaseURL follows cmd/web: API_URL wins, otherwise the API on the configured local port.
+func apiBaseURL() string {
+	if apiURL := os.Getenv("API_URL"); apiURL != "" {
+		return apiURL
+	}
+	return fmt.Sprintf("http://localhost:%s", config.Load().Port)
+}
+
+// fetchRSSHeadlines calls the top headlines endpoint of the given source. A source
+// the API does not know is reported by name instead of as a bare 404.
+func fetchRSSHeadlines(baseURL, source string, limit int, filter string) (*handlers.HeadlinesResponse, error) {
+	query := url.Values{"limit": {fmt.Sprint(limit)}}
+	if filter != "" {
+		query.Set("filter", filter)
+	}
+	apiURL := fmt.Sprintf("%s/api/rss/%s/top5?%s", baseURL, url.PathEscape(source), query.Encode())
+
+	client := &http.Client{Timeout: rssRequestTimeout}
+	resp, err := client.Get(apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach API: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("unknown source %q (see %s/api/rss/sources)", source, baseURL)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	var response handlers.HeadlinesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode API response: %w", err)
+	}
+	return &response, nil
+}
+
+// printHeadlineList writes the headlines as a numbered list with their links.
+func printHeadlineList(out io.Writer, response *handlers.HeadlinesResponse) {
+	if len(response.Headlines) == 0 {
+		fmt.Fprintln(out, "No headlines found.")
+		return
+	}
+
+	bold := color.New(color.Bold).SprintFunc()
+	for i, headline := range response.Headlines {
+		fmt.Fprintf(out, "%2d. %s\n    %s\n", i+1, bold(headline.Title), headline.Link)
+	}
+}
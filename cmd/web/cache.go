@@ -0,0 +1,54 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/f00b455/golang-template/internal/handlers"
+)
+
+// DefaultCacheTTL is how long API responses are reused before the API is asked again.
+const DefaultCacheTTL = 30 * time.Second
+
+// cachedResponse is an API response together with the time it was fetched.
+type cachedResponse struct {
+	response  *handlers.HeadlinesResponse
+	fetchedAt time.Time
+}
+
+// responseCache keeps successful API responses per filter so repeated page
+// loads within the TTL don't each hit the API.
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+}
+
+var headlinesCache = &responseCache{entries: make(map[string]cachedResponse)}
+
+// get returns the cached response for filter if it is younger than ttl.
+func (rc *responseCache) get(filter string, ttl time.Duration) (*handlers.HeadlinesResponse, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	entry, ok := rc.entries[filter]
+	if !ok || time.Since(entry.fetchedAt) >= ttl {
+		return nil, false
+	}
+	return entry.response, true
+}
+
+// set stores response for filter.
+func (rc *responseCache) set(filter string, response *handlers.HeadlinesResponse) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.entries[filter] = cachedResponse{response: response, fetchedAt: time.Now()}
+}
+
+// reset drops all cached responses.
+func (rc *responseCache) reset() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.entries = make(map[string]cachedResponse)
+}
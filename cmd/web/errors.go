@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// internalErrorMessage is shown to users instead of the details of an internal error.
+const internalErrorMessage = "Something went wrong, please try again later"
+
+// ErrorPageData is the data of the error.html template.
+type ErrorPageData struct {
+	Title   string
+	Message string
+}
+
+// renderError logs err and answers with status and a generic message: JSON for
+// /api/ paths, the error.html page otherwise. The details of err never reach the client.
+func renderError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	log.Printf("Error serving %s: %v", r.URL.Path, err)
+
+	if strings.HasPrefix(r.URL.Path, "/api/") {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": internalErrorMessage})
+		return
+	}
+
+	var page bytes.Buffer
+	data := ErrorPageData{Title: http.StatusText(status), Message: internalErrorMessage}
+	if templates == nil || templates.ExecuteTemplate(&page, "error.html", data) != nil {
+		http.Error(w, internalErrorMessage, status)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = page.WriteTo(w)
+}
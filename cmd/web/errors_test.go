@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHomeHandler_TemplateErrorDoesNotLeakDetails(t *testing.T) {
+	setupWebTest(t, nil)
+	// Replace the page with one that fails while executing
+	templates = template.Must(templates.New("index.html").Parse(`<p id="partial">{{.NoSuchField}}</p>`))
+
+	w := httptest.NewRecorder()
+	homeHandler(w, httptest.NewRequest("GET", "/", nil))
+
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Body.String(), internalErrorMessage)
+	assert.NotContains(t, w.Body.String(), "NoSuchField")
+	assert.NotContains(t, w.Body.String(), `id="partial"`, "no half-rendered page is sent")
+}
+
+func TestRenderError_JSONForAPIPaths(t *testing.T) {
+	setupWebTest(t, nil)
+
+	w := httptest.NewRecorder()
+	renderError(w, httptest.NewRequest("GET", "/api/headlines", nil), http.StatusInternalServerError, errors.New("template: secret detail"))
+
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, internalErrorMessage, body["error"])
+	assert.NotContains(t, w.Body.String(), "secret detail")
+}
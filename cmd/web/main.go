@@ -1,14 +1,20 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html"
 	"html/template"
 	"log"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/f00b455/golang-template/internal/config"
@@ -21,17 +27,34 @@ const (
 	APITimeout      = 5 * time.Second
 	DefaultWebPort  = "8080"
 	MaxFilterLength = 100
+	// DefaultShutdownTimeout bounds how long in-flight requests may take during shutdown.
+	DefaultShutdownTimeout = 10 * time.Second
+	ReadHeaderTimeout      = 5 * time.Second
+	// HealthProbeTimeout bounds the upstream reachability check of /healthz.
+	HealthProbeTimeout = 2 * time.Second
+	// DefaultDisplayTZ is the IANA time zone headline dates are shown in.
+	DefaultDisplayTZ = "Europe/Berlin"
+	// DefaultFallbackMessage is shown when headlines cannot be fetched or the feed is empty.
+	DefaultFallbackMessage = "Unable to fetch headlines"
 )
 
 type PageData struct {
-	Title     string
-	Headlines []shared.RssHeadline
-	UpdatedAt string
-	Error     string
+	Title           string
+	Headlines       []shared.RssHeadline
+	UpdatedAt       string
+	Error           string
+	FallbackMessage string
 }
 
 type WebConfig struct {
-	APIURL string
+	APIURL          string
+	FallbackMessage string
+	ShutdownTimeout time.Duration
+	// CacheTTL is how long API responses are reused; zero disables the cache.
+	CacheTTL time.Duration
+	// DisplayLocation is the time zone formatDate shows dates in, resolved
+	// once from DISPLAY_TZ at startup; nil means time.Local.
+	DisplayLocation *time.Location
 }
 
 var (
@@ -43,34 +66,92 @@ func main() {
 	// Load config
 	cfg := config.Load()
 
+	displayLocation, err := loadDisplayLocation(getEnv("DISPLAY_TZ", DefaultDisplayTZ))
+	if err != nil {
+		log.Fatal("Invalid DISPLAY_TZ:", err)
+	}
+
 	// Initialize web config
 	webConfig = &WebConfig{
-		APIURL: getEnv("API_URL", fmt.Sprintf("http://localhost:%s", cfg.Port)),
+		APIURL:          getEnv("API_URL", fmt.Sprintf("http://localhost:%s", cfg.Port)),
+		FallbackMessage: getEnv("WEB_FALLBACK_MESSAGE", DefaultFallbackMessage),
+		ShutdownTimeout: getDurationEnv("WEB_SHUTDOWN_TIMEOUT", DefaultShutdownTimeout),
+		CacheTTL:        getNonNegativeDurationEnv("WEB_CACHE_TTL", DefaultCacheTTL),
+		DisplayLocation: displayLocation,
 	}
 
-	// Parse templates
-	funcMap := template.FuncMap{
-		"formatDate": formatDate,
+	port := getEnv("PORT", DefaultWebPort)
+
+	server, err := newServer(":"+port, "templates/*.html")
+	if err != nil {
+		log.Fatal("Failed to parse templates:", err)
 	}
 
-	templates = template.Must(template.New("").Funcs(funcMap).ParseGlob("templates/*.html"))
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// Set up routes
-	http.HandleFunc("/", homeHandler)
-	http.HandleFunc("/api/headlines", headlinesAPIHandler)
-	http.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir("static"))))
+	log.Printf("Web server starting on port %s", port)
+	log.Printf("Visit http://localhost:%s", port)
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = DefaultWebPort
+	if err := run(ctx, server, webConfig.ShutdownTimeout); err != nil {
+		log.Fatal("Web server error:", err)
 	}
+	log.Println("Web server stopped")
+}
 
-	log.Printf("Web server starting on port %s", port)
-	log.Printf("Visit http://localhost:%s", port)
+// newServer parses the templates and builds the HTTP server with all routes registered.
+func newServer(addr, templatePattern string) (*http.Server, error) {
+	parsed, err := parseTemplates(templatePattern)
+	if err != nil {
+		return nil, err
+	}
+	templates = parsed
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", homeHandler)
+	mux.HandleFunc("/api/headlines", headlinesAPIHandler)
+	mux.HandleFunc("/api/headlines/stream", headlinesStreamHandler)
+	mux.HandleFunc("/api/search", searchAPIHandler)
+	mux.HandleFunc("/healthz", healthHandler)
+	mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir("static"))))
+
+	// Shutdown does not cancel request contexts, so long-lived streams learn
+	// about it from a channel closed by the shutdown hook
+	stopping := make(chan struct{})
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: ReadHeaderTimeout,
+		BaseContext: func(net.Listener) context.Context {
+			return context.WithValue(context.Background(), shutdownSignalKey{}, (<-chan struct{})(stopping))
+		},
+	}
+	server.RegisterOnShutdown(func() { close(stopping) })
+	return server, nil
+}
 
-	if err := http.ListenAndServe(":"+port, nil); err != nil {
-		log.Fatal("Failed to start web server:", err)
+// run serves until ctx is cancelled, then shuts the server down gracefully
+// so in-flight requests can finish within shutdownTimeout.
+func run(ctx context.Context, server *http.Server, shutdownTimeout time.Duration) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+		}
+		close(serveErr)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
 	}
+
+	log.Println("Shutting down web server...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	return server.Shutdown(shutdownCtx)
 }
 
 func homeHandler(w http.ResponseWriter, r *http.Request) {
@@ -78,30 +159,31 @@ func homeHandler(w http.ResponseWriter, r *http.Request) {
 	headlines, err := fetchHeadlines("")
 
 	data := PageData{
-		Title:     "SPIEGEL Headlines",
-		Headlines: headlines,
-		UpdatedAt: time.Now().Format("15:04:05"),
+		Title:           "SPIEGEL Headlines",
+		Headlines:       headlines,
+		UpdatedAt:       time.Now().Format("15:04:05"),
+		FallbackMessage: webConfig.FallbackMessage,
 	}
 
 	if err != nil {
-		data.Error = "Unable to fetch headlines"
+		data.Error = webConfig.FallbackMessage
 	}
 
-	if err := templates.ExecuteTemplate(w, "index.html", data); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	// Render into a buffer so a failing template never leaves a half-written page
+	var page bytes.Buffer
+	if err := templates.ExecuteTemplate(&page, "index.html", data); err != nil {
+		renderError(w, r, http.StatusInternalServerError, err)
+		return
 	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = page.WriteTo(w)
 }
 
 func headlinesAPIHandler(w http.ResponseWriter, r *http.Request) {
-	filter := r.URL.Query().Get("filter")
-
-	// Validate and sanitize filter input
-	if len(filter) > MaxFilterLength {
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(map[string]string{"error": "Filter too long"})
+	filter, ok := parseFilter(w, r)
+	if !ok {
 		return
 	}
-	filter = html.EscapeString(filter)
 
 	headlinesResp, err := fetchHeadlinesWithData(filter)
 
@@ -110,7 +192,7 @@ func headlinesAPIHandler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		log.Printf("Error fetching headlines: %v", err)
 		w.WriteHeader(http.StatusServiceUnavailable)
-		_ = json.NewEncoder(w).Encode(map[string]string{"error": "Unable to fetch headlines"})
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": webConfig.FallbackMessage})
 		return
 	}
 
@@ -122,38 +204,77 @@ func headlinesAPIHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func fetchHeadlines(filter string) ([]shared.RssHeadline, error) {
-	// Fetch from the API server
-	apiURL := fmt.Sprintf("%s/api/rss/spiegel/top5", webConfig.APIURL)
-
-	if filter != "" {
-		apiURL += "?filter=" + url.QueryEscape(filter)
+// parseFilter validates and sanitizes the filter query parameter. It writes a
+// 400 response and reports false when the filter is too long.
+func parseFilter(w http.ResponseWriter, r *http.Request) (string, bool) {
+	filter := r.URL.Query().Get("filter")
+	if len(filter) > MaxFilterLength {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "Filter too long"})
+		return "", false
 	}
+	return html.EscapeString(filter), true
+}
 
-	client := &http.Client{
-		Timeout: APITimeout,
+// healthHandler always answers 200 while the web server is up. It also reports
+// whether the API is reachable, without failing the check when it is not.
+func healthHandler(w http.ResponseWriter, r *http.Request) {
+	upstream := "reachable"
+	if !apiReachable() {
+		upstream = "unreachable"
 	}
 
-	resp, err := client.Get(apiURL)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"status":   "ok",
+		"upstream": upstream,
+	})
+}
+
+// apiReachable reports whether the API answers its cheap greet endpoint without a server error.
+func apiReachable() bool {
+	client := &http.Client{Timeout: HealthProbeTimeout}
+	resp, err := client.Get(webConfig.APIURL + "/api/greet")
 	if err != nil {
-		return nil, err
+		return false
 	}
-	defer func() { _ = resp.Body.Close() }()
+	_ = resp.Body.Close()
+	return resp.StatusCode < http.StatusInternalServerError
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+// parseTemplates parses the HTML templates matching pattern with the web funcmap.
+func parseTemplates(pattern string) (*template.Template, error) {
+	funcMap := template.FuncMap{
+		"formatDate": formatDate,
 	}
+	return template.New("").Funcs(funcMap).ParseGlob(pattern)
+}
 
-	var response handlers.HeadlinesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+func fetchHeadlines(filter string) ([]shared.RssHeadline, error) {
+	response, err := fetchHeadlinesWithData(filter)
+	if err != nil {
 		return nil, err
 	}
-
 	return response.Headlines, nil
 }
 
+// fetchHeadlinesWithData returns the API response for filter, reusing a cached
+// response younger than webConfig.CacheTTL.
 func fetchHeadlinesWithData(filter string) (*handlers.HeadlinesResponse, error) {
-	// Single API call that returns both headlines and totalCount
+	if cached, ok := headlinesCache.get(filter, webConfig.CacheTTL); ok {
+		return cached, nil
+	}
+
+	response, err := requestHeadlines(filter)
+	if err != nil {
+		return nil, err
+	}
+	headlinesCache.set(filter, response)
+	return response, nil
+}
+
+// requestHeadlines calls the API once; the response carries both headlines and totalCount.
+func requestHeadlines(filter string) (*handlers.HeadlinesResponse, error) {
 	apiURL := fmt.Sprintf("%s/api/rss/spiegel/top5", webConfig.APIURL)
 
 	if filter != "" {
@@ -189,18 +310,45 @@ func formatDate(dateStr string) string {
 		return dateStr
 	}
 
-	// Convert to Berlin timezone
-	loc, err := time.LoadLocation("Europe/Berlin")
-	if err != nil {
-		loc = time.Local
+	loc := time.Local
+	if webConfig != nil && webConfig.DisplayLocation != nil {
+		loc = webConfig.DisplayLocation
 	}
-
 	return t.In(loc).Format("02.01.2006 15:04")
 }
 
+// loadDisplayLocation resolves the IANA time zone name (e.g. "America/New_York")
+// headline dates are shown in.
+func loadDisplayLocation(tz string) (*time.Location, error) {
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q: %w", tz, err)
+	}
+	return loc, nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+// getDurationEnv parses a duration such as "15s" from the environment, falling back on absence or error.
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// getNonNegativeDurationEnv is getDurationEnv for settings where "0" is meaningful,
+// such as WEB_CACHE_TTL=0 disabling the cache; only negative values fall back.
+func getNonNegativeDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"html/template"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/f00b455/golang-template/internal/handlers"
+	"github.com/f00b455/golang-template/pkg/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupWebTest points the web server at a mock API returning the given headlines.
+func setupWebTest(t *testing.T, headlines []shared.RssHeadline) *httptest.Server {
+	t.Helper()
+
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(handlers.HeadlinesResponse{Headlines: headlines})
+	}))
+	t.Cleanup(apiServer.Close)
+
+	templates = template.Must(parseTemplates("../../templates/*.html"))
+	webConfig = &WebConfig{
+		APIURL:          apiServer.URL,
+		FallbackMessage: DefaultFallbackMessage,
+	}
+	headlinesCache.reset()
+	searchCache.reset()
+	return apiServer
+}
+
+func TestHomeHandler_RendersConfiguredFallbackMessage(t *testing.T) {
+	setupWebTest(t, []shared.RssHeadline{})
+	webConfig.FallbackMessage = "Keine Schlagzeilen verfügbar"
+
+	w := httptest.NewRecorder()
+	homeHandler(w, httptest.NewRequest("GET", "/", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Keine Schlagzeilen verfügbar")
+	assert.NotContains(t, w.Body.String(), DefaultFallbackMessage)
+}
+
+func TestHomeHandler_RendersFallbackMessageOnAPIError(t *testing.T) {
+	setupWebTest(t, nil)
+	webConfig.APIURL = "http://127.0.0.1:0"
+	webConfig.FallbackMessage = "Service temporarily unavailable"
+
+	w := httptest.NewRecorder()
+	homeHandler(w, httptest.NewRequest("GET", "/", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Service temporarily unavailable")
+}
+
+func TestHeadlinesAPIHandler_SingleUpstreamCallWithCount(t *testing.T) {
+	var upstreamCalls int32
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&upstreamCalls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(handlers.HeadlinesResponse{
+			Headlines:  []shared.RssHeadline{{Title: "Politik heute"}},
+			TotalCount: 42,
+		})
+	}))
+	defer apiServer.Close()
+	setupWebTest(t, nil)
+	webConfig.APIURL = apiServer.URL
+
+	w := httptest.NewRecorder()
+	headlinesAPIHandler(w, httptest.NewRequest("GET", "/api/headlines?filter=Politik", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Headlines  []shared.RssHeadline `json:"headlines"`
+		TotalCount int                  `json:"totalCount"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 42, body.TotalCount)
+	assert.Len(t, body.Headlines, 1)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&upstreamCalls))
+}
+
+// countingAPI serves headlines and counts how often each filter was requested.
+func countingAPI(t *testing.T, calls *sync.Map) *httptest.Server {
+	t.Helper()
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		counter, _ := calls.LoadOrStore(r.URL.Query().Get("filter"), new(int32))
+		atomic.AddInt32(counter.(*int32), 1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(handlers.HeadlinesResponse{
+			Headlines: []shared.RssHeadline{{Title: "Politik heute"}},
+		})
+	}))
+	t.Cleanup(apiServer.Close)
+	return apiServer
+}
+
+func callCount(calls *sync.Map, filter string) int32 {
+	counter, ok := calls.Load(filter)
+	if !ok {
+		return 0
+	}
+	return atomic.LoadInt32(counter.(*int32))
+}
+
+func TestFetchHeadlines_CachesPerFilterWithinTTL(t *testing.T) {
+	var calls sync.Map
+	setupWebTest(t, nil)
+	webConfig.APIURL = countingAPI(t, &calls).URL
+	webConfig.CacheTTL = time.Minute
+
+	for i := 0; i < 3; i++ {
+		homeHandler(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+		headlinesAPIHandler(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/headlines", nil))
+		headlinesAPIHandler(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/headlines?filter=Politik", nil))
+	}
+
+	assert.Equal(t, int32(1), callCount(&calls, ""))
+	assert.Equal(t, int32(1), callCount(&calls, "Politik"))
+}
+
+func TestFetchHeadlines_RefetchesAfterTTL(t *testing.T) {
+	var calls sync.Map
+	setupWebTest(t, nil)
+	webConfig.APIURL = countingAPI(t, &calls).URL
+	webConfig.CacheTTL = 20 * time.Millisecond
+
+	_, err := fetchHeadlines("")
+	require.NoError(t, err)
+	time.Sleep(30 * time.Millisecond)
+	_, err = fetchHeadlines("")
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), callCount(&calls, ""))
+}
+
+func TestFetchHeadlines_DoesNotCacheErrors(t *testing.T) {
+	setupWebTest(t, nil)
+	webConfig.APIURL = "http://127.0.0.1:0"
+	webConfig.CacheTTL = time.Minute
+
+	_, err := fetchHeadlines("")
+	require.Error(t, err)
+
+	var calls sync.Map
+	webConfig.APIURL = countingAPI(t, &calls).URL
+	_, err = fetchHeadlines("")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), callCount(&calls, ""))
+}
+
+func TestNewServer_RegistersRoutes(t *testing.T) {
+	setupWebTest(t, []shared.RssHeadline{{Title: "Routed Headline", PublishedAt: "2025-09-24T08:05:00Z"}})
+
+	server, err := newServer(":0", "../../templates/*.html")
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	server.Handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Routed Headline")
+
+	w = httptest.NewRecorder()
+	server.Handler.ServeHTTP(w, httptest.NewRequest("GET", "/api/headlines", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	server.Handler.ServeHTTP(w, httptest.NewRequest("GET", "/healthz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestHealthHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		apiURL   func(apiServer *httptest.Server) string
+		upstream string
+	}{
+		{name: "api reachable", apiURL: func(s *httptest.Server) string { return s.URL }, upstream: "reachable"},
+		{name: "api unreachable", apiURL: func(*httptest.Server) string { return "http://127.0.0.1:0" }, upstream: "unreachable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiServer := setupWebTest(t, nil)
+			webConfig.APIURL = tt.apiURL(apiServer)
+
+			w := httptest.NewRecorder()
+			healthHandler(w, httptest.NewRequest("GET", "/healthz", nil))
+
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			var body map[string]string
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, "ok", body["status"])
+			assert.Equal(t, tt.upstream, body["upstream"])
+		})
+	}
+}
+
+func TestNewServer_InvalidTemplatePattern(t *testing.T) {
+	_, err := newServer(":0", "does-not-exist/*.html")
+	assert.Error(t, err)
+}
+
+func TestRun_ShutsDownGracefullyOnCancel(t *testing.T) {
+	setupWebTest(t, nil)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	server, err := newServer(addr, "../../templates/*.html")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- run(ctx, server, time.Second) }()
+
+	require.Eventually(t, func() bool {
+		resp, err := http.Get("http://" + addr + "/static/")
+		if err != nil {
+			return false
+		}
+		_ = resp.Body.Close()
+		return true
+	}, 2*time.Second, 10*time.Millisecond)
+
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("server did not shut down in time")
+	}
+
+	_, err = http.Get("http://" + addr + "/")
+	assert.Error(t, err)
+}
+
+func TestFormatDate_DisplayLocation(t *testing.T) {
+	setupWebTest(t, nil)
+
+	tests := []struct {
+		tz       string
+		expected string
+	}{
+		{tz: DefaultDisplayTZ, expected: "24.09.2025 10:05"},
+		{tz: "America/New_York", expected: "24.09.2025 04:05"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tz, func(t *testing.T) {
+			loc, err := loadDisplayLocation(tt.tz)
+			require.NoError(t, err)
+			webConfig.DisplayLocation = loc
+			assert.Equal(t, tt.expected, formatDate("2025-09-24T08:05:00Z"))
+		})
+	}
+
+	t.Run("unset", func(t *testing.T) {
+		webConfig.DisplayLocation = nil
+		expected := time.Date(2025, 9, 24, 8, 5, 0, 0, time.UTC).In(time.Local).Format("02.01.2006 15:04")
+		assert.Equal(t, expected, formatDate("2025-09-24T08:05:00Z"))
+	})
+}
+
+func TestLoadDisplayLocation_RejectsUnknownZone(t *testing.T) {
+	_, err := loadDisplayLocation("Not/AZone")
+	assert.ErrorContains(t, err, `unknown time zone "Not/AZone"`)
+}
+
+func TestGetDurationEnv(t *testing.T) {
+	t.Setenv("WEB_SHUTDOWN_TIMEOUT", "3s")
+	assert.Equal(t, 3*time.Second, getDurationEnv("WEB_SHUTDOWN_TIMEOUT", DefaultShutdownTimeout))
+
+	t.Setenv("WEB_SHUTDOWN_TIMEOUT", "not-a-duration")
+	assert.Equal(t, DefaultShutdownTimeout, getDurationEnv("WEB_SHUTDOWN_TIMEOUT", DefaultShutdownTimeout))
+}
+
+func TestGetNonNegativeDurationEnv(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{value: "0", expected: 0},
+		{value: "5s", expected: 5 * time.Second},
+		{value: "-1s", expected: DefaultCacheTTL},
+		{value: "", expected: DefaultCacheTTL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("WEB_CACHE_TTL", tt.value)
+			assert.Equal(t, tt.expected, getNonNegativeDurationEnv("WEB_CACHE_TTL", DefaultCacheTTL))
+		})
+	}
+}
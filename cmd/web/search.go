@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/f00b455/golang-template/internal/handlers"
+)
+
+// searchCache keeps search results per search term, separately from the headlines.
+var searchCache = &responseCache{entries: make(map[string]cachedResponse)}
+
+// searchAPIHandler proxies /api/search?q= to the API's search endpoint.
+func searchAPIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "Missing search term"})
+		return
+	}
+	if len(query) > MaxFilterLength {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "Search term too long"})
+		return
+	}
+
+	client := &http.Client{Timeout: APITimeout}
+	searchResp, err := fetchSearchResults(client, query)
+	if err != nil {
+		log.Printf("Error searching headlines: %v", err)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": webConfig.FallbackMessage})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"headlines":  searchResp.Headlines,
+		"updatedAt":  time.Now().Format(time.RFC3339),
+		"query":      query,
+		"totalCount": searchResp.TotalCount,
+	})
+}
+
+// fetchSearchResults returns the API's results for query, reusing a cached
+// response younger than webConfig.CacheTTL.
+func fetchSearchResults(client *http.Client, query string) (*handlers.HeadlinesResponse, error) {
+	if cached, ok := searchCache.get(query, webConfig.CacheTTL); ok {
+		return cached, nil
+	}
+
+	response, err := requestSearch(client, query)
+	if err != nil {
+		return nil, err
+	}
+	searchCache.set(query, response)
+	return response, nil
+}
+
+// requestSearch calls the API's search endpoint once with client.
+func requestSearch(client *http.Client, query string) (*handlers.HeadlinesResponse, error) {
+	apiURL := fmt.Sprintf("%s/api/rss/spiegel/search?q=%s", webConfig.APIURL, url.QueryEscape(query))
+
+	resp, err := client.Get(apiURL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	var response handlers.HeadlinesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
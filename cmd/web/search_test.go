@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/f00b455/golang-template/internal/handlers"
+	"github.com/f00b455/golang-template/pkg/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// searchAPI serves search results echoing the search term and counts the calls per term.
+func searchAPI(t *testing.T, calls *sync.Map) *httptest.Server {
+	t.Helper()
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/rss/spiegel/search", r.URL.Path)
+		query := r.URL.Query().Get("q")
+		counter, _ := calls.LoadOrStore(query, new(int32))
+		atomic.AddInt32(counter.(*int32), 1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(handlers.HeadlinesResponse{
+			Headlines:  []shared.RssHeadline{{Title: "Treffer für " + query}},
+			TotalCount: 1,
+		})
+	}))
+	t.Cleanup(apiServer.Close)
+	return apiServer
+}
+
+func TestSearchAPIHandler_ProxiesAndCachesPerQuery(t *testing.T) {
+	var calls sync.Map
+	setupWebTest(t, nil)
+	webConfig.APIURL = searchAPI(t, &calls).URL
+	webConfig.CacheTTL = time.Minute
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		searchAPIHandler(w, httptest.NewRequest("GET", "/api/search?q=Berlin+Wahl", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var body struct {
+			Headlines  []shared.RssHeadline `json:"headlines"`
+			Query      string               `json:"query"`
+			TotalCount int                  `json:"totalCount"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.Len(t, body.Headlines, 1)
+		assert.Equal(t, "Treffer für Berlin Wahl", body.Headlines[0].Title)
+		assert.Equal(t, "Berlin Wahl", body.Query)
+		assert.Equal(t, 1, body.TotalCount)
+	}
+	searchAPIHandler(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/search?q=Sport", nil))
+
+	assert.Equal(t, int32(1), callCount(&calls, "Berlin Wahl"), "a repeated search within the TTL is served from the cache")
+	assert.Equal(t, int32(1), callCount(&calls, "Sport"))
+}
+
+func TestSearchAPIHandler_RejectsInvalidQuery(t *testing.T) {
+	setupWebTest(t, nil)
+
+	for _, target := range []string{"/api/search", "/api/search?q=" + strings.Repeat("a", MaxFilterLength+1)} {
+		w := httptest.NewRecorder()
+		searchAPIHandler(w, httptest.NewRequest("GET", target, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, target)
+	}
+}
+
+func TestFetchSearchResults_APIError(t *testing.T) {
+	setupWebTest(t, nil)
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer apiServer.Close()
+	webConfig.APIURL = apiServer.URL
+
+	_, err := fetchSearchResults(apiServer.Client(), "Berlin")
+	assert.EqualError(t, err, "API returned status 503")
+}
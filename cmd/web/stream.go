@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// headlinesEvent is the payload of a /api/headlines/stream event.
+type headlinesEvent struct {
+	Headlines  interface{} `json:"headlines"`
+	TotalCount int         `json:"totalCount"`
+	Filter     string      `json:"filter"`
+}
+
+// shutdownSignalKey is the request context key of the channel closed when the server shuts down.
+type shutdownSignalKey struct{}
+
+// shutdownSignal returns the channel closed when the server handling ctx shuts
+// down, or nil (never ready) for requests served without one.
+func shutdownSignal(ctx context.Context) <-chan struct{} {
+	stopping, _ := ctx.Value(shutdownSignalKey{}).(<-chan struct{})
+	return stopping
+}
+
+// headlinesStreamHandler pushes the headlines as Server-Sent Events. It polls the
+// API every CacheTTL and only emits an event when the headlines changed. The
+// stream ends when the client disconnects or the server shuts down.
+func headlinesStreamHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	filter, ok := parseFilter(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(streamInterval())
+	defer ticker.Stop()
+
+	stopping := shutdownSignal(r.Context())
+	var last []byte
+	for {
+		payload, err := headlinesEventData(filter)
+		if err != nil {
+			log.Printf("Error fetching headlines for stream: %v", err)
+		} else if !bytes.Equal(payload, last) {
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", payload); err != nil {
+				return
+			}
+			flusher.Flush()
+			last = payload
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-stopping:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// headlinesEventData fetches the headlines for filter and encodes them as event data.
+func headlinesEventData(filter string) ([]byte, error) {
+	response, err := fetchHeadlinesWithData(filter)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(headlinesEvent{
+		Headlines:  response.Headlines,
+		TotalCount: response.TotalCount,
+		Filter:     filter,
+	})
+}
+
+// streamInterval is how often the stream polls the API; it follows the cache TTL.
+func streamInterval() time.Duration {
+	if webConfig.CacheTTL > 0 {
+		return webConfig.CacheTTL
+	}
+	return DefaultCacheTTL
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/f00b455/golang-template/internal/handlers"
+	"github.com/f00b455/golang-template/pkg/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readEvent reads the next "data:" line of an SSE stream and decodes it.
+func readEvent(t *testing.T, reader *bufio.Reader) headlinesEvent {
+	t.Helper()
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		if data, ok := strings.CutPrefix(line, "data: "); ok {
+			var event headlinesEvent
+			require.NoError(t, json.Unmarshal([]byte(data), &event))
+			return event
+		}
+	}
+}
+
+// openStream connects to the stream handler and returns a reader plus a channel
+// closed once the handler has returned.
+func openStream(t *testing.T, ctx context.Context) (*bufio.Reader, <-chan struct{}) {
+	t.Helper()
+	done := make(chan struct{})
+	webServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(done)
+		headlinesStreamHandler(w, r)
+	}))
+	t.Cleanup(webServer.Close)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", webServer.URL+"/api/headlines/stream", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	return bufio.NewReader(resp.Body), done
+}
+
+func TestHeadlinesStreamHandler_SendsEventAndStopsOnDisconnect(t *testing.T) {
+	setupWebTest(t, []shared.RssHeadline{{Title: "Live Headline"}})
+	webConfig.CacheTTL = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reader, done := openStream(t, ctx)
+
+	event := readEvent(t, reader)
+	require.Len(t, event.Headlines, 1)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream handler did not return after the client disconnected")
+	}
+}
+
+func TestHeadlinesStreamHandler_EmitsOnlyOnChange(t *testing.T) {
+	var calls int32
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The headline changes on every third poll.
+		n := atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(handlers.HeadlinesResponse{
+			Headlines: []shared.RssHeadline{{Title: fmt.Sprintf("Headline %d", (n-1)/3)}},
+		})
+	}))
+	defer apiServer.Close()
+	setupWebTest(t, nil)
+	webConfig.APIURL = apiServer.URL
+	webConfig.CacheTTL = 5 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reader, _ := openStream(t, ctx)
+
+	first := readEvent(t, reader)
+	second := readEvent(t, reader)
+
+	assert.NotEqual(t, first.Headlines, second.Headlines)
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&calls), int32(4), "unchanged polls must not produce events")
+}
+
+func TestHeadlinesStreamHandler_RejectsLongFilter(t *testing.T) {
+	setupWebTest(t, nil)
+
+	w := httptest.NewRecorder()
+	headlinesStreamHandler(w, httptest.NewRequest("GET", "/api/headlines/stream?filter="+strings.Repeat("a", MaxFilterLength+1), nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRun_ShutsDownWithOpenStream(t *testing.T) {
+	setupWebTest(t, []shared.RssHeadline{{Title: "Live Headline"}})
+	webConfig.CacheTTL = time.Hour
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	server, err := newServer(addr, "../../templates/*.html")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- run(ctx, server, 5*time.Second) }()
+
+	var resp *http.Response
+	require.Eventually(t, func() bool {
+		resp, err = http.Get("http://" + addr + "/api/headlines/stream")
+		return err == nil
+	}, 2*time.Second, 10*time.Millisecond)
+	defer func() { _ = resp.Body.Close() }()
+	readEvent(t, bufio.NewReader(resp.Body))
+
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("an open stream kept the server from shutting down")
+	}
+}
@@ -0,0 +1,21 @@
+package docs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwaggerDoc_IncludesRSSPaths(t *testing.T) {
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(SwaggerInfo.ReadDoc()), &doc))
+
+	for _, path := range []string{"/rss/spiegel/latest", "/rss/spiegel/top5", "/rss/spiegel/export"} {
+		assert.Contains(t, doc.Paths, path)
+		assert.Contains(t, doc.Paths[path], "get", "%s should document a GET operation", path)
+	}
+}
@@ -17,7 +17,7 @@ Feature: SPIEGEL RSS API Endpoints
     And the headline should have a title
     And the headline should have a link
     And the headline should have a publishedAt timestamp
-    And the headline should have source "SPIEGEL"
+    And the headline should have source "SPIEGEL ONLINE"
 
   @happy-path
   Scenario: Get top 5 SPIEGEL RSS headlines
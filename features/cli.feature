@@ -44,4 +44,34 @@ Feature: Hello-World CLI Application
   Scenario: Display help with --help flag
     When I run hello-cli with "--help" flag
     Then I should see a help message
-    And the help should contain "A colorful Hello-World CLI application"
\ No newline at end of file
+    And the help should contain "A colorful Hello-World CLI application"
+  @scripting
+  Scenario: Output the greeting as JSON for scripts
+    When I run hello-cli with "--json" flag
+    Then the command should complete successfully
+    And the output should be a JSON greeting for "World"
+    And the output should not contain ANSI escape codes
+    And the output should not contain box characters
+
+  @happy-path
+  Scenario: Greet several names with the greet subcommand
+    When I run hello-cli with arguments "greet Alice Bob Charlie"
+    Then the command should complete successfully
+    And I should see a greeting message for "Alice"
+    And I should see a greeting message for "Bob"
+    And I should see a greeting message for "Charlie"
+
+  @rss
+  Scenario: Print headlines from the API
+    Given the headlines API returns "Erste Meldung, Zweite Meldung"
+    When I run hello-cli with arguments "rss --source spiegel --limit 5"
+    Then the command should complete successfully
+    And the output should contain "1. Erste Meldung"
+    And the output should contain "2. Zweite Meldung"
+
+  @rss @error-handling
+  Scenario: Report an unreachable API
+    Given the headlines API is unreachable
+    When I run hello-cli with arguments "rss"
+    Then the command should fail
+    And the output should contain "Error: failed to reach API"
@@ -2,7 +2,11 @@ package features
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -10,6 +14,8 @@ import (
 	"testing"
 
 	"github.com/cucumber/godog"
+	"github.com/f00b455/golang-template/internal/handlers"
+	"github.com/f00b455/golang-template/pkg/shared"
 )
 
 type cliFeatureContext struct {
@@ -17,6 +23,8 @@ type cliFeatureContext struct {
 	commandError  error
 	exitCode      int
 	binaryPath    string
+	apiServer     *httptest.Server
+	extraEnv      []string
 }
 
 func (ctx *cliFeatureContext) iHaveTheHelloCLICommandAvailable() error {
@@ -52,10 +60,15 @@ func (ctx *cliFeatureContext) iRunHelloCLIWithFlag(flag string) error {
 	return ctx.runCommand(flag)
 }
 
+func (ctx *cliFeatureContext) iRunHelloCLIWithArguments(arguments string) error {
+	return ctx.runCommand(strings.Fields(arguments)...)
+}
+
 func (ctx *cliFeatureContext) runCommand(args ...string) error {
 	// Set test environment to use faster delays
 	cmd := exec.Command(ctx.binaryPath, args...)
 	cmd.Env = append(os.Environ(), "GO_ENV=test")
+	cmd.Env = append(cmd.Env, ctx.extraEnv...)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -75,6 +88,44 @@ func (ctx *cliFeatureContext) runCommand(args ...string) error {
 	return nil
 }
 
+func (ctx *cliFeatureContext) theHeadlinesAPIReturns(titles string) error {
+	var headlines []shared.RssHeadline
+	for i, title := range strings.Split(titles, ",") {
+		headlines = append(headlines, shared.RssHeadline{
+			Title: strings.TrimSpace(title),
+			Link:  fmt.Sprintf("https://example.com/%d", i+1),
+		})
+	}
+
+	ctx.apiServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(handlers.HeadlinesResponse{Headlines: headlines})
+	}))
+	ctx.extraEnv = append(ctx.extraEnv, "API_URL="+ctx.apiServer.URL)
+	return nil
+}
+
+func (ctx *cliFeatureContext) theHeadlinesAPIIsUnreachable() error {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	ctx.extraEnv = append(ctx.extraEnv, "API_URL="+server.URL)
+	return nil
+}
+
+func (ctx *cliFeatureContext) theCommandShouldFail() error {
+	if ctx.exitCode == 0 {
+		return fmt.Errorf("expected command to fail, but it succeeded: %s", ctx.commandOutput)
+	}
+	return nil
+}
+
+func (ctx *cliFeatureContext) theOutputShouldContain(expected string) error {
+	if !strings.Contains(ctx.commandOutput, expected) {
+		return fmt.Errorf("expected '%s' not found in output:\n%s", expected, ctx.commandOutput)
+	}
+	return nil
+}
+
 func (ctx *cliFeatureContext) theCommandShouldCompleteSuccessfully() error {
 	if ctx.exitCode != 0 {
 		return fmt.Errorf("command failed with exit code %d: %s", ctx.exitCode, ctx.commandOutput)
@@ -160,19 +211,57 @@ func (ctx *cliFeatureContext) theHelpShouldContain(expectedText string) error {
 	return nil
 }
 
+func (ctx *cliFeatureContext) theOutputShouldBeAJSONGreetingFor(name string) error {
+	var result struct {
+		Greeting string `json:"greeting"`
+		Name     string `json:"name"`
+	}
+	if err := json.Unmarshal([]byte(ctx.commandOutput), &result); err != nil {
+		return fmt.Errorf("output is not valid JSON: %w\n%s", err, ctx.commandOutput)
+	}
+	if !strings.Contains(result.Greeting, fmt.Sprintf("Hello, %s!", name)) {
+		return fmt.Errorf("expected greeting for '%s', got %q", name, result.Greeting)
+	}
+	if result.Name != name {
+		return fmt.Errorf("expected name %q, got %q", name, result.Name)
+	}
+	return nil
+}
+
+func (ctx *cliFeatureContext) theOutputShouldNotContainANSIEscapeCodes() error {
+	if strings.Contains(ctx.commandOutput, "\x1b[") {
+		return fmt.Errorf("unexpected ANSI escape codes in output:\n%q", ctx.commandOutput)
+	}
+	return nil
+}
+
+func (ctx *cliFeatureContext) theOutputShouldNotContainBoxCharacters() error {
+	if strings.ContainsAny(ctx.commandOutput, "╔╗╚╝║═") {
+		return fmt.Errorf("unexpected box drawing characters in output:\n%s", ctx.commandOutput)
+	}
+	return nil
+}
+
 func InitializeCLIScenario(ctx *godog.ScenarioContext) {
 	featureCtx := &cliFeatureContext{}
 
 	// Background steps
 	ctx.Step(`^I have the hello-cli command available$`, featureCtx.iHaveTheHelloCLICommandAvailable)
 
+	// Setup steps
+	ctx.Step(`^the headlines API returns "([^"]*)"$`, featureCtx.theHeadlinesAPIReturns)
+	ctx.Step(`^the headlines API is unreachable$`, featureCtx.theHeadlinesAPIIsUnreachable)
+
 	// Action steps
 	ctx.Step(`^I run hello-cli without parameters$`, featureCtx.iRunHelloCLIWithoutParameters)
 	ctx.Step(`^I run hello-cli with name "([^"]*)"$`, featureCtx.iRunHelloCLIWithName)
 	ctx.Step(`^I run hello-cli with "([^"]*)" flag$`, featureCtx.iRunHelloCLIWithFlag)
+	ctx.Step(`^I run hello-cli with arguments "([^"]*)"$`, featureCtx.iRunHelloCLIWithArguments)
 
 	// Assertion steps
 	ctx.Step(`^the command should complete successfully$`, featureCtx.theCommandShouldCompleteSuccessfully)
+	ctx.Step(`^the command should fail$`, featureCtx.theCommandShouldFail)
+	ctx.Step(`^the output should contain "([^"]*)"$`, featureCtx.theOutputShouldContain)
 	ctx.Step(`^I should see a spinner message "([^"]*)"$`, featureCtx.iShouldSeeASpinnerMessage)
 	ctx.Step(`^I should see a progress message "([^"]*)"$`, featureCtx.iShouldSeeAProgressMessage)
 	ctx.Step(`^I should see a greeting message for "([^"]*)"$`, featureCtx.iShouldSeeAGreetingMessageFor)
@@ -183,6 +272,19 @@ func InitializeCLIScenario(ctx *godog.ScenarioContext) {
 	ctx.Step(`^the output should match the core package format$`, featureCtx.theOutputShouldMatchTheCorePackageFormat)
 	ctx.Step(`^I should see a help message$`, featureCtx.iShouldSeeAHelpMessage)
 	ctx.Step(`^the help should contain "([^"]*)"$`, featureCtx.theHelpShouldContain)
+	ctx.Step(`^the output should be a JSON greeting for "([^"]*)"$`, featureCtx.theOutputShouldBeAJSONGreetingFor)
+	ctx.Step(`^the output should not contain ANSI escape codes$`, featureCtx.theOutputShouldNotContainANSIEscapeCodes)
+	ctx.Step(`^the output should not contain box characters$`, featureCtx.theOutputShouldNotContainBoxCharacters)
+
+	// Cleanup
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		if featureCtx.apiServer != nil {
+			featureCtx.apiServer.Close()
+			featureCtx.apiServer = nil
+		}
+		featureCtx.extraEnv = nil
+		return c, nil
+	})
 }
 
 func TestCLIFeatures(t *testing.T) {
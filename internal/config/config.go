@@ -1,25 +1,226 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
-// Config holds the application configuration.
+// MinRSSFetchItems is the smallest accepted RSSMaxFetchItems: the most headlines
+// a single API request may return.
+const MinRSSFetchItems = 200
+
+// knownEnvironments lists the accepted values of ENV.
+var knownEnvironments = []string{"development", "test", "staging", "production"}
+
+// Default CORS settings allow the local frontend dev server.
+var (
+	defaultCORSOrigins = []string{"http://localhost:3000"}
+	defaultCORSMethods = []string{"POST", "OPTIONS", "GET", "PUT", "DELETE"}
+	defaultCORSHeaders = []string{
+		"Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization",
+		"accept", "origin", "Cache-Control", "X-Requested-With",
+	}
+)
+
+// Config holds the application configuration. The yaml keys are used by
+// LoadFromFile; JSON files use the same keys.
 type Config struct {
-	Port          string
-	Environment   string
-	SpiegelRSSURL string
+	Port          string `yaml:"port"`
+	Environment   string `yaml:"environment"`
+	SpiegelRSSURL string `yaml:"spiegel_rss_url"`
+	// SpiegelRSSFallbackURLs are tried in order when SpiegelRSSURL cannot be fetched.
+	SpiegelRSSFallbackURLs []string `yaml:"spiegel_rss_fallback_urls"`
+	// MaxConcurrentExports caps how many export requests may run at the same time.
+	MaxConcurrentExports int `yaml:"export_max_concurrent"`
+	// RSSRequestTimeout bounds a feed fetch, including all of its retries.
+	RSSRequestTimeout time.Duration `yaml:"rss_request_timeout"`
+	// RSSAutoRefreshInterval is how often the feed is refetched in the background; 0 disables it.
+	RSSAutoRefreshInterval time.Duration `yaml:"rss_auto_refresh_interval"`
+	// RSSMaxConcurrentFetches caps how many feed fetches may run at the same time, across all sources.
+	RSSMaxConcurrentFetches int `yaml:"rss_max_concurrent_fetches"`
+	// RSSRetryCount is how many times a failed feed fetch is retried (network errors and 5xx only).
+	RSSRetryCount int `yaml:"rss_retry_count"`
+	// RSSRetryBaseDelay is the backoff before the first retry; it doubles for every further retry.
+	RSSRetryBaseDelay time.Duration `yaml:"rss_retry_base_delay"`
+	// RSSBreakerThreshold is the number of consecutive feed failures that opens the circuit breaker.
+	RSSBreakerThreshold int `yaml:"rss_breaker_threshold"`
+	// RSSBreakerCooldown is how long the open breaker rejects fetches before probing the feed again.
+	RSSBreakerCooldown time.Duration `yaml:"rss_breaker_cooldown"`
+	// RSSMaxBodyBytes caps the size of a (decompressed) feed response; larger feeds are rejected.
+	RSSMaxBodyBytes int `yaml:"rss_max_body_bytes"`
+	// RSSMinFetchInterval is the least time between two upstream fetches of the same feed;
+	// cache misses in between are served the last fetched headlines. 0 disables the guard.
+	RSSMinFetchInterval time.Duration `yaml:"rss_min_fetch_interval"`
+	// RSSMaxFetchItems is how many feed items are parsed and cached. It is kept above the
+	// 200 items a request may ask for so filtered requests still have enough to choose from.
+	RSSMaxFetchItems int `yaml:"rss_max_fetch_items"`
+	// RSSMaxIdleConns caps the idle keep-alive connections kept open to all feed hosts.
+	RSSMaxIdleConns int `yaml:"rss_max_idle_conns"`
+	// RSSMaxIdleConnsPerHost caps the idle keep-alive connections kept open per feed host.
+	RSSMaxIdleConnsPerHost int `yaml:"rss_max_idle_conns_per_host"`
+	// RSSIdleConnTimeout is how long an idle keep-alive connection is kept before it is closed.
+	RSSIdleConnTimeout time.Duration `yaml:"rss_idle_conn_timeout"`
+	// RSSUserAgent is the User-Agent header sent to the feed.
+	RSSUserAgent string `yaml:"rss_user_agent"`
+	// RSSAccept is the Accept header sent to the feed.
+	RSSAccept string `yaml:"rss_accept"`
+	// RSSResponseCacheSize is how many distinct (source, limit, filter) responses are kept.
+	RSSResponseCacheSize int `yaml:"rss_response_cache_size"`
+	// RSSResponseCacheTTL is how long a cached response is served before it is rebuilt.
+	RSSResponseCacheTTL time.Duration `yaml:"rss_response_cache_ttl"`
+	// CORSAllowedOrigins lists the origins allowed to call the API ("*" allows any origin).
+	CORSAllowedOrigins []string `yaml:"cors_allowed_origins"`
+	// CORSAllowedMethods lists the methods announced in Access-Control-Allow-Methods.
+	CORSAllowedMethods []string `yaml:"cors_allowed_methods"`
+	// CORSAllowedHeaders lists the headers announced in Access-Control-Allow-Headers.
+	CORSAllowedHeaders []string `yaml:"cors_allowed_headers"`
+	// MaxQueryLength caps the length of a request's raw query string; longer requests are rejected.
+	MaxQueryLength int `yaml:"max_query_length"`
+	// GreetMaxNameLength is the longest name, in runes, the greet endpoints accept.
+	GreetMaxNameLength int `yaml:"greet_max_name_length"`
+	// AdminToken enables the /api/admin endpoints when set; requests must send it as a Bearer token.
+	AdminToken string `yaml:"admin_token"`
 }
 
 // Load creates a new Config instance with values from environment variables.
+// When CONFIG_FILE names a readable file its values replace the defaults, and
+// environment variables still take precedence over both.
 func Load() *Config {
+	base := defaults()
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := readConfigFile(path, base); err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				log.Printf("Ignoring config file: %v", err)
+			}
+			base = defaults()
+		}
+	}
+	return withEnv(base)
+}
+
+// defaults returns the configuration used when neither a file nor the environment set a value.
+func defaults() *Config {
+	return &Config{
+		Port:                    "3002",
+		Environment:             "development",
+		SpiegelRSSURL:           "https://www.spiegel.de/schlagzeilen/index.rss",
+		MaxConcurrentExports:    4,
+		RSSRequestTimeout:       2 * time.Second,
+		RSSAutoRefreshInterval:  5 * time.Minute,
+		RSSMaxConcurrentFetches: 1,
+		RSSRetryCount:           2,
+		RSSRetryBaseDelay:       100 * time.Millisecond,
+		RSSBreakerThreshold:     5,
+		RSSBreakerCooldown:      30 * time.Second,
+		RSSMaxBodyBytes:         5 << 20,
+		RSSMinFetchInterval:     30 * time.Second,
+		RSSMaxFetchItems:        250,
+		RSSMaxIdleConns:         100,
+		RSSMaxIdleConnsPerHost:  10,
+		RSSIdleConnTimeout:      90 * time.Second,
+		RSSUserAgent:            "Mozilla/5.0 (compatible; Golang-Template/1.0)",
+		RSSAccept:               "application/rss+xml, application/xml, text/xml",
+		RSSResponseCacheSize:    128,
+		RSSResponseCacheTTL:     5 * time.Minute,
+		CORSAllowedOrigins:      defaultCORSOrigins,
+		CORSAllowedMethods:      defaultCORSMethods,
+		CORSAllowedHeaders:      defaultCORSHeaders,
+		MaxQueryLength:          2048,
+		GreetMaxNameLength:      100,
+	}
+}
+
+// withEnv returns base with every value overridden by its environment variable, if set.
+func withEnv(base *Config) *Config {
 	return &Config{
-		Port:          getEnv("PORT", "3002"),
-		Environment:   getEnv("ENV", "development"),
-		SpiegelRSSURL: getEnv("SPIEGEL_RSS_URL", "https://www.spiegel.de/schlagzeilen/index.rss"),
+		Port:                    getEnv("PORT", base.Port),
+		Environment:             getEnv("ENV", base.Environment),
+		SpiegelRSSURL:           getEnv("SPIEGEL_RSS_URL", base.SpiegelRSSURL),
+		SpiegelRSSFallbackURLs:  getListEnv("SPIEGEL_RSS_FALLBACK_URLS", base.SpiegelRSSFallbackURLs),
+		MaxConcurrentExports:    getIntEnv("EXPORT_MAX_CONCURRENT", base.MaxConcurrentExports, 1),
+		RSSRequestTimeout:       getDurationEnv("RSS_REQUEST_TIMEOUT", base.RSSRequestTimeout),
+		RSSAutoRefreshInterval:  getDurationEnv("RSS_AUTO_REFRESH_INTERVAL", base.RSSAutoRefreshInterval),
+		RSSMaxConcurrentFetches: getIntEnv("RSS_MAX_CONCURRENT_FETCHES", base.RSSMaxConcurrentFetches, 1),
+		RSSRetryCount:           getIntEnv("RSS_RETRY_COUNT", base.RSSRetryCount, 0),
+		RSSRetryBaseDelay:       getDurationEnv("RSS_RETRY_BASE_DELAY", base.RSSRetryBaseDelay),
+		RSSBreakerThreshold:     getIntEnv("RSS_BREAKER_THRESHOLD", base.RSSBreakerThreshold, 1),
+		RSSBreakerCooldown:      getDurationEnv("RSS_BREAKER_COOLDOWN", base.RSSBreakerCooldown),
+		RSSMaxBodyBytes:         getIntEnv("RSS_MAX_BODY_BYTES", base.RSSMaxBodyBytes, 1),
+		RSSMinFetchInterval:     getDurationEnv("RSS_MIN_FETCH_INTERVAL", base.RSSMinFetchInterval),
+		RSSMaxFetchItems:        getIntEnv("RSS_MAX_FETCH_ITEMS", base.RSSMaxFetchItems, 1),
+		RSSMaxIdleConns:         getIntEnv("RSS_MAX_IDLE_CONNS", base.RSSMaxIdleConns, 1),
+		RSSMaxIdleConnsPerHost:  getIntEnv("RSS_MAX_IDLE_CONNS_PER_HOST", base.RSSMaxIdleConnsPerHost, 1),
+		RSSIdleConnTimeout:      getDurationEnv("RSS_IDLE_CONN_TIMEOUT", base.RSSIdleConnTimeout),
+		RSSUserAgent:            getEnv("RSS_USER_AGENT", base.RSSUserAgent),
+		RSSAccept:               getEnv("RSS_ACCEPT", base.RSSAccept),
+		RSSResponseCacheSize:    getIntEnv("RSS_RESPONSE_CACHE_SIZE", base.RSSResponseCacheSize, 1),
+		RSSResponseCacheTTL:     getDurationEnv("RSS_RESPONSE_CACHE_TTL", base.RSSResponseCacheTTL),
+		CORSAllowedOrigins:      getListEnv("CORS_ALLOWED_ORIGINS", base.CORSAllowedOrigins),
+		CORSAllowedMethods:      getListEnv("CORS_ALLOWED_METHODS", base.CORSAllowedMethods),
+		CORSAllowedHeaders:      getListEnv("CORS_ALLOWED_HEADERS", base.CORSAllowedHeaders),
+		MaxQueryLength:          getIntEnv("MAX_QUERY_LENGTH", base.MaxQueryLength, 1),
+		GreetMaxNameLength:      getIntEnv("GREET_MAX_NAME_LENGTH", base.GreetMaxNameLength, 1),
+		AdminToken:              getEnv("ADMIN_TOKEN", base.AdminToken),
 	}
 }
 
+// Validate reports the first invalid setting: the RSS URL and its fallbacks must
+// be absolute http(s) URLs, the request timeout positive, the fetch limit at least
+// MinRSSFetchItems, the port an integer between 1 and 65535 and the environment
+// one of knownEnvironments.
+func (c *Config) Validate() error {
+	if err := validateFeedURL(c.SpiegelRSSURL); err != nil {
+		return fmt.Errorf("invalid SPIEGEL_RSS_URL: %w", err)
+	}
+	for _, fallback := range c.SpiegelRSSFallbackURLs {
+		if err := validateFeedURL(fallback); err != nil {
+			return fmt.Errorf("invalid SPIEGEL_RSS_FALLBACK_URLS: %w", err)
+		}
+	}
+
+	if c.RSSRequestTimeout <= 0 {
+		return fmt.Errorf("invalid RSS_REQUEST_TIMEOUT %v: must be positive", c.RSSRequestTimeout)
+	}
+
+	if c.RSSMaxFetchItems < MinRSSFetchItems {
+		return fmt.Errorf("invalid RSS_MAX_FETCH_ITEMS %d: must be at least %d", c.RSSMaxFetchItems, MinRSSFetchItems)
+	}
+
+	port, err := strconv.Atoi(c.Port)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("invalid PORT %q: must be an integer between 1 and 65535", c.Port)
+	}
+
+	for _, env := range knownEnvironments {
+		if c.Environment == env {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid ENV %q: must be one of %s", c.Environment, strings.Join(knownEnvironments, ", "))
+}
+
+// validateFeedURL checks that raw is an absolute http or https URL.
+func validateFeedURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("%q must use http or https", raw)
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("%q has no host", raw)
+	}
+	return nil
+}
+
 // getEnv returns the value of the environment variable or the default value if not set.
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -27,3 +228,38 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getIntEnv returns the integer value of the environment variable or the default value
+// if it is not set, not an integer or below minValue.
+func getIntEnv(key string, defaultValue, minValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value < minValue {
+		return defaultValue
+	}
+	return value
+}
+
+// getListEnv returns the comma-separated values of the environment variable, trimmed
+// and without empty entries, or the default value if it is not set or empty.
+func getListEnv(key string, defaultValue []string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return defaultValue
+	}
+	return values
+}
+
+// getDurationEnv returns the duration value (e.g. "250ms") of the environment variable
+// or the default value if it is not set or not a valid non-negative duration.
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validConfig() *Config {
+	return &Config{
+		Port:              "3002",
+		Environment:       "development",
+		SpiegelRSSURL:     "https://www.spiegel.de/schlagzeilen/index.rss",
+		RSSRequestTimeout: 2 * time.Second,
+		RSSMaxFetchItems:  250,
+	}
+}
+
+func TestValidate_DefaultsAreValid(t *testing.T) {
+	assert.NoError(t, validConfig().Validate())
+	assert.NoError(t, Load().Validate())
+}
+
+func TestValidate_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(c *Config)
+		wantErr string
+	}{
+		{name: "blank RSS URL", modify: func(c *Config) { c.SpiegelRSSURL = "" }, wantErr: "invalid SPIEGEL_RSS_URL"},
+		{name: "relative RSS URL", modify: func(c *Config) { c.SpiegelRSSURL = "/index.rss" }, wantErr: "invalid SPIEGEL_RSS_URL"},
+		{name: "non-http RSS URL", modify: func(c *Config) { c.SpiegelRSSURL = "ftp://example.com/feed" }, wantErr: "must use http or https"},
+		{name: "RSS URL without host", modify: func(c *Config) { c.SpiegelRSSURL = "https://" }, wantErr: "has no host"},
+		{name: "unparseable RSS URL", modify: func(c *Config) { c.SpiegelRSSURL = "http://[::1" }, wantErr: "invalid SPIEGEL_RSS_URL"},
+		{name: "relative fallback URL", modify: func(c *Config) { c.SpiegelRSSFallbackURLs = []string{"https://b.example.com/rss", "/index.rss"} }, wantErr: "invalid SPIEGEL_RSS_FALLBACK_URLS"},
+		{name: "zero request timeout", modify: func(c *Config) { c.RSSRequestTimeout = 0 }, wantErr: "invalid RSS_REQUEST_TIMEOUT"},
+		{name: "fetch limit below the return limit", modify: func(c *Config) { c.RSSMaxFetchItems = 199 }, wantErr: "invalid RSS_MAX_FETCH_ITEMS"},
+		{name: "non-numeric port", modify: func(c *Config) { c.Port = "http" }, wantErr: "invalid PORT"},
+		{name: "port zero", modify: func(c *Config) { c.Port = "0" }, wantErr: "invalid PORT"},
+		{name: "port too large", modify: func(c *Config) { c.Port = "65536" }, wantErr: "invalid PORT"},
+		{name: "unknown environment", modify: func(c *Config) { c.Environment = "prod" }, wantErr: "invalid ENV"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLoad_CORSLists(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", " https://a.example.com, ,https://b.example.com ")
+	t.Setenv("CORS_ALLOWED_METHODS", "")
+
+	cfg := Load()
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, cfg.CORSAllowedOrigins)
+	assert.Contains(t, cfg.CORSAllowedMethods, "GET")
+}
+
+func TestLoad_RSSRequestHeaders(t *testing.T) {
+	cfg := Load()
+	assert.Equal(t, "Mozilla/5.0 (compatible; Golang-Template/1.0)", cfg.RSSUserAgent)
+	assert.Equal(t, "application/rss+xml, application/xml, text/xml", cfg.RSSAccept)
+
+	t.Setenv("RSS_USER_AGENT", "FeedBot/2.0")
+	t.Setenv("RSS_ACCEPT", "application/atom+xml")
+	cfg = Load()
+	assert.Equal(t, "FeedBot/2.0", cfg.RSSUserAgent)
+	assert.Equal(t, "application/atom+xml", cfg.RSSAccept)
+}
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadFromFile_YAML(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+port: "4000"
+environment: staging
+rss_breaker_cooldown: 1m
+cors_allowed_origins:
+  - https://app.example.com
+unknown_key: ignored
+`)
+
+	cfg, err := LoadFromFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "4000", cfg.Port)
+	assert.Equal(t, "staging", cfg.Environment)
+	assert.Equal(t, time.Minute, cfg.RSSBreakerCooldown)
+	assert.Equal(t, []string{"https://app.example.com"}, cfg.CORSAllowedOrigins)
+	// Keys missing from the file keep their defaults.
+	assert.Equal(t, "https://www.spiegel.de/schlagzeilen/index.rss", cfg.SpiegelRSSURL)
+	assert.Equal(t, 4, cfg.MaxConcurrentExports)
+}
+
+func TestLoadFromFile_JSON(t *testing.T) {
+	path := writeConfigFile(t, "config.json", `{"port": "4001", "rss_retry_count": 0}`)
+
+	cfg, err := LoadFromFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "4001", cfg.Port)
+	assert.Equal(t, 0, cfg.RSSRetryCount)
+}
+
+func TestLoadFromFile_IgnoresValuesBelowMinimum(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+export_max_concurrent: 0
+rss_max_concurrent_fetches: 0
+rss_breaker_threshold: -1
+rss_response_cache_size: 0
+rss_retry_count: 0
+`)
+
+	cfg, err := LoadFromFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 4, cfg.MaxConcurrentExports)
+	assert.Equal(t, 1, cfg.RSSMaxConcurrentFetches)
+	assert.Equal(t, 5, cfg.RSSBreakerThreshold)
+	assert.Equal(t, 128, cfg.RSSResponseCacheSize)
+	// Zero retries is a valid setting and is kept.
+	assert.Equal(t, 0, cfg.RSSRetryCount)
+}
+
+func TestLoadFromFile_EnvOverridesFile(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "port: \"4000\"\nenvironment: staging\n")
+	t.Setenv("PORT", "5000")
+
+	cfg, err := LoadFromFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "5000", cfg.Port)
+	assert.Equal(t, "staging", cfg.Environment)
+}
+
+func TestLoadFromFile_Errors(t *testing.T) {
+	_, err := LoadFromFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	_, err = LoadFromFile(writeConfigFile(t, "broken.yaml", "port: [unclosed"))
+	assert.ErrorContains(t, err, "parsing config file")
+}
+
+func TestLoad_ConfigFileEnv(t *testing.T) {
+	t.Setenv("CONFIG_FILE", writeConfigFile(t, "config.yaml", "admin_token: from-file\n"))
+	assert.Equal(t, "from-file", Load().AdminToken)
+
+	t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+	cfg := Load()
+	assert.Equal(t, "", cfg.AdminToken)
+	assert.Equal(t, "3002", cfg.Port)
+}
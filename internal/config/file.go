@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadFromFile reads a YAML or JSON config file (keys as in the Config yaml tags)
+// on top of the defaults. Unknown keys are ignored and environment variables
+// still override the file's values.
+func LoadFromFile(path string) (*Config, error) {
+	base := defaults()
+	if err := readConfigFile(path, base); err != nil {
+		return nil, err
+	}
+	return withEnv(base), nil
+}
+
+// readConfigFile decodes the file at path into cfg, leaving absent keys untouched.
+// JSON is accepted as well since it is a subset of YAML. Like their environment
+// variables, integer values below their minimum are ignored.
+func readConfigFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+	before := *cfg
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	keepMinimums(cfg, &before)
+	return nil
+}
+
+// keepMinimums restores the values from before that cfg sets below the minimum
+// withEnv enforces for the same setting.
+func keepMinimums(cfg, before *Config) {
+	settings := []struct {
+		key      string
+		value    *int
+		previous int
+		minValue int
+	}{
+		{"export_max_concurrent", &cfg.MaxConcurrentExports, before.MaxConcurrentExports, 1},
+		{"rss_max_concurrent_fetches", &cfg.RSSMaxConcurrentFetches, before.RSSMaxConcurrentFetches, 1},
+		{"rss_retry_count", &cfg.RSSRetryCount, before.RSSRetryCount, 0},
+		{"rss_breaker_threshold", &cfg.RSSBreakerThreshold, before.RSSBreakerThreshold, 1},
+		{"rss_max_body_bytes", &cfg.RSSMaxBodyBytes, before.RSSMaxBodyBytes, 1},
+		{"rss_max_fetch_items", &cfg.RSSMaxFetchItems, before.RSSMaxFetchItems, 1},
+		{"rss_max_idle_conns", &cfg.RSSMaxIdleConns, before.RSSMaxIdleConns, 1},
+		{"rss_max_idle_conns_per_host", &cfg.RSSMaxIdleConnsPerHost, before.RSSMaxIdleConnsPerHost, 1},
+		{"rss_response_cache_size", &cfg.RSSResponseCacheSize, before.RSSResponseCacheSize, 1},
+		{"max_query_length", &cfg.MaxQueryLength, before.MaxQueryLength, 1},
+		{"greet_max_name_length", &cfg.GreetMaxNameLength, before.GreetMaxNameLength, 1},
+	}
+	for _, setting := range settings {
+		if *setting.value < setting.minValue {
+			log.Printf("Ignoring config file %s %d: must be at least %d", setting.key, *setting.value, setting.minValue)
+			*setting.value = setting.previous
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/f00b455/golang-template/internal/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker_OpensAndFailsFast(t *testing.T) {
+	var attempts int32
+	serverError := func(req *http.Request) (*http.Response, error) {
+		return statusResponse(req, http.StatusInternalServerError, ""), nil
+	}
+	handler := newRetryTestHandler(flakyClient(&attempts, serverError, serverError, serverError))
+	handler.cfg.RSSRetryCount = 0
+	handler.breaker = newCircuitBreaker(3, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		_, err := handler.fetchRSSFeed(context.Background())
+		require.ErrorIs(t, err, ErrUpstreamStatus)
+	}
+	require.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+
+	// The breaker is open: further calls fail fast without reaching the transport.
+	for i := 0; i < 5; i++ {
+		_, err := handler.fetchRSSFeed(context.Background())
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/rss/spiegel/top5", nil)
+	handler.GetTop5(c)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, CodeCircuitOpen, response.Code)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+}
+
+func TestCircuitBreaker_HalfOpenProbe(t *testing.T) {
+	now := time.Date(2023, 9, 24, 10, 0, 0, 0, time.UTC)
+	breaker := newCircuitBreaker(2, 30*time.Second)
+	breaker.now = func() time.Time { return now }
+
+	breaker.recordFailure()
+	breaker.recordFailure()
+	assert.False(t, breaker.allow(), "open during cooldown")
+
+	now = now.Add(31 * time.Second)
+	assert.True(t, breaker.allow(), "first call after cooldown probes the upstream")
+	assert.False(t, breaker.allow(), "only one probe at a time")
+
+	breaker.recordFailure()
+	assert.False(t, breaker.allow(), "failed probe reopens the breaker")
+
+	now = now.Add(31 * time.Second)
+	require.True(t, breaker.allow())
+	breaker.recordSuccess()
+	assert.True(t, breaker.allow(), "successful probe closes the breaker")
+	assert.True(t, breaker.allow())
+}
+
+func TestCircuitBreaker_CancelledProbeReleasesHalfOpen(t *testing.T) {
+	var healthy atomic.Bool
+	handler := newRetryTestHandler(&http.Client{Transport: &testutil.MockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			if err := req.Context().Err(); err != nil {
+				return nil, err
+			}
+			if !healthy.Load() {
+				return statusResponse(req, http.StatusBadGateway, ""), nil
+			}
+			return statusResponse(req, http.StatusOK, MockRSSResponse), nil
+		},
+	}})
+	handler.cfg.RSSRetryCount = 0
+	handler.breaker = newCircuitBreaker(1, 10*time.Millisecond)
+
+	_, err := handler.fetchRSSFeed(context.Background())
+	require.ErrorIs(t, err, ErrUpstreamStatus)
+	time.Sleep(20 * time.Millisecond)
+
+	// The half-open probe is cancelled by its caller before reaching the feed
+	healthy.Store(true)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = handler.fetchRSSFeed(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+
+	body, err := handler.fetchRSSFeed(context.Background())
+	require.NoError(t, err, "the next call must probe the feed again")
+	assert.Contains(t, body, "Headline 1")
+}
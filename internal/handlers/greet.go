@@ -1,18 +1,25 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/f00b455/golang-template/internal/config"
+	"github.com/f00b455/golang-template/pkg/core"
 	"github.com/f00b455/golang-template/pkg/shared"
 	"github.com/gin-gonic/gin"
 )
 
 // GreetHandler handles greeting requests.
-type GreetHandler struct{}
+type GreetHandler struct {
+	// maxNameLength is the longest name, in runes, that is greeted
+	maxNameLength int
+}
 
 // NewGreetHandler creates a new GreetHandler.
 func NewGreetHandler() *GreetHandler {
-	return &GreetHandler{}
+	return &GreetHandler{maxNameLength: config.Load().GreetMaxNameLength}
 }
 
 // GreetResponse represents the response for the greet endpoint.
@@ -20,20 +27,123 @@ type GreetResponse struct {
 	Message string `json:"message" example:"Hello, World!"`
 }
 
+// GreetRequest is the body of POST /api/greet.
+type GreetRequest struct {
+	Name   string `json:"name" example:"Alice"`
+	Prefix string `json:"prefix" example:">> "`
+	Suffix string `json:"suffix" example:" <<"`
+	Lang   string `json:"lang" example:"de"`
+}
+
+// maxBatchNames caps how many names a single batch request may greet.
+const maxBatchNames = 100
+
+// BatchGreetRequest is the body of POST /api/greet/batch.
+type BatchGreetRequest struct {
+	Names []string `json:"names" example:"Alice,Bob"`
+}
+
+// BatchGreeting is a single greeting of a batch response.
+type BatchGreeting struct {
+	Name     string `json:"name" example:"Alice"`
+	Greeting string `json:"greeting" example:"Hello, Alice!"`
+}
+
+// BatchGreetResponse represents the response for the batch greet endpoint.
+type BatchGreetResponse struct {
+	Greetings []BatchGreeting `json:"greetings"`
+}
+
 // Greet handles GET /api/greet
 // @Summary      Greet endpoint
 // @Description  Returns a greeting message
 // @Tags         greet
 // @Accept       json
 // @Produce      json
-// @Param        name    query     string  false  "Name to greet" default(World)
+// @Param        name    query     string  false  "Name to greet (100 characters max by default)" default(World)
 // @Success      200     {object}  GreetResponse
+// @Failure      400     {object}  ErrorResponse
 // @Router       /greet [get]
 func (h *GreetHandler) Greet(c *gin.Context) {
 	name := c.DefaultQuery("name", "World")
-	message := shared.Greet(name)
+	if err := core.ValidateName(name, h.maxNameLength); err != nil {
+		respondBadRequest(c, err)
+		return
+	}
+	message := shared.Greet(core.StripControlCharacters(name))
 
 	c.JSON(http.StatusOK, GreetResponse{
 		Message: message,
 	})
 }
+
+// GreetPost handles POST /api/greet
+// @Summary      Greet with options
+// @Description  Returns a greeting for the name in the request body, optionally localized and wrapped in a prefix and suffix
+// @Tags         greet
+// @Accept       json
+// @Produce      json
+// @Param        request  body      GreetRequest  true  "Name and greeting options"
+// @Success      200      {object}  GreetResponse
+// @Failure      400      {object}  ErrorResponse
+// @Router       /greet [post]
+func (h *GreetHandler) GreetPost(c *gin.Context) {
+	var req GreetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		respondBadRequest(c, fmt.Errorf("name must not be empty"))
+		return
+	}
+	if err := core.ValidateName(req.Name, h.maxNameLength); err != nil {
+		respondBadRequest(c, err)
+		return
+	}
+
+	message := core.FooGreet(core.FooConfig{Prefix: req.Prefix, Suffix: req.Suffix, Lang: req.Lang}, req.Name)
+	c.JSON(http.StatusOK, GreetResponse{Message: message})
+}
+
+// GreetBatch handles POST /api/greet/batch
+// @Summary      Greet several names
+// @Description  Returns a greeting for every name in the request body (at most 100 names)
+// @Tags         greet
+// @Accept       json
+// @Produce      json
+// @Param        request  body      BatchGreetRequest  true  "Names to greet"
+// @Success      200      {object}  BatchGreetResponse
+// @Failure      400      {object}  ErrorResponse
+// @Router       /greet/batch [post]
+func (h *GreetHandler) GreetBatch(c *gin.Context) {
+	var req BatchGreetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	if len(req.Names) == 0 {
+		respondBadRequest(c, fmt.Errorf("names must contain at least one name"))
+		return
+	}
+	if len(req.Names) > maxBatchNames {
+		respondBadRequest(c, fmt.Errorf("names must contain at most %d names", maxBatchNames))
+		return
+	}
+	for _, name := range req.Names {
+		if err := core.ValidateName(name, h.maxNameLength); err != nil {
+			respondBadRequest(c, err)
+			return
+		}
+	}
+
+	greetings := make([]BatchGreeting, len(req.Names))
+	for i, name := range req.Names {
+		greetings[i] = BatchGreeting{
+			Name:     core.SanitizeName(name),
+			Greeting: core.FooGreet(core.FooConfig{}, name),
+		}
+	}
+
+	c.JSON(http.StatusOK, BatchGreetResponse{Greetings: greetings})
+}
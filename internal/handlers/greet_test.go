@@ -2,12 +2,15 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGreetHandler(t *testing.T) {
@@ -98,4 +101,165 @@ func TestGreetHandler_JSONResponse(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, response.Message)
-}
\ No newline at end of file
+}
+func doGreetBatchRequest(t *testing.T, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+
+	req := httptest.NewRequest("POST", "/greet/batch", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	NewGreetHandler().GreetBatch(c)
+	return w
+}
+
+func TestGreetHandler_Batch(t *testing.T) {
+	w := doGreetBatchRequest(t, `{"names":["Alice","Bob"]}`)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response BatchGreetResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []BatchGreeting{
+		{Name: "Alice", Greeting: "Hello, Alice!"},
+		{Name: "Bob", Greeting: "Hello, Bob!"},
+	}, response.Greetings)
+}
+
+func TestGreetHandler_BatchRejectsInvalidInput(t *testing.T) {
+	tooMany := make([]string, maxBatchNames+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("%q", fmt.Sprintf("Name%d", i))
+	}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "empty array", body: `{"names":[]}`},
+		{name: "missing names", body: `{}`},
+		{name: "malformed JSON", body: `{"names":`},
+		{name: "too many names", body: `{"names":[` + strings.Join(tooMany, ",") + `]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doGreetBatchRequest(t, tt.body)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, CodeBadRequest, response.Code)
+		})
+	}
+}
+
+func TestGreetHandler_BatchAtCap(t *testing.T) {
+	names := make([]string, maxBatchNames)
+	for i := range names {
+		names[i] = fmt.Sprintf("%q", fmt.Sprintf("Name%d", i))
+	}
+
+	w := doGreetBatchRequest(t, `{"names":[`+strings.Join(names, ",")+`]}`)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response BatchGreetResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Greetings, maxBatchNames)
+}
+
+func doGreetPostRequest(t *testing.T, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+
+	req := httptest.NewRequest("POST", "/greet", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	NewGreetHandler().GreetPost(c)
+	return w
+}
+
+func TestGreetHandler_Post(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{name: "name only", body: `{"name":"Alice"}`, expected: "Hello, Alice!"},
+		{name: "prefix and suffix", body: `{"name":"Bob","prefix":">> ","suffix":" <<"}`, expected: ">> Hello, Bob! <<"},
+		{name: "localized", body: `{"name":"Clara","lang":"de"}`, expected: "Hallo, Clara!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doGreetPostRequest(t, tt.body)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var response GreetResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expected, response.Message)
+		})
+	}
+}
+
+func TestGreetHandler_PostRejectsInvalidInput(t *testing.T) {
+	for _, body := range []string{`{}`, `{"name":""}`, `{"name":"   "}`, `{"name":`} {
+		t.Run(body, func(t *testing.T) {
+			w := doGreetPostRequest(t, body)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, CodeBadRequest, response.Code)
+		})
+	}
+}
+
+func TestGreetHandler_NameValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{name: "name at the limit", query: strings.Repeat("a", 100), expectedStatus: http.StatusOK, expectedMsg: "Hello, " + strings.Repeat("a", 100) + "!"},
+		{name: "over-long name", query: strings.Repeat("a", 101), expectedStatus: http.StatusBadRequest},
+		{name: "newline stripped", query: "Ali%0Ace", expectedStatus: http.StatusOK, expectedMsg: "Hello, Alice!"},
+		{name: "escape sequence stripped", query: "%1B%5B31mAlice", expectedStatus: http.StatusOK, expectedMsg: "Hello, [31mAlice!"},
+		{name: "only control characters", query: "%0A%0D", expectedStatus: http.StatusOK, expectedMsg: "Error: Name cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/greet?name="+tt.query, nil)
+			NewGreetHandler().Greet(c)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusBadRequest {
+				var response ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "name too long (max 100 characters)", response.Error)
+				return
+			}
+			var response GreetResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedMsg, response.Message)
+		})
+	}
+}
+
+func TestGreetHandler_RejectsLongNamesInBodies(t *testing.T) {
+	long := strings.Repeat("a", 101)
+
+	assert.Equal(t, http.StatusBadRequest, doGreetPostRequest(t, `{"name":"`+long+`"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, doGreetBatchRequest(t, `{"names":["Alice","`+long+`"]}`).Code)
+}
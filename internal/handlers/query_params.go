@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multiValueParams may be repeated (?category=a&category=b) and are read with queryList.
+var multiValueParams = map[string]bool{
+	"category": true,
+}
+
+// checkDuplicateParams rejects repeated single-value query parameters when strict=true.
+// Without strict mode the first value wins, matching Gin's c.Query.
+func checkDuplicateParams(c *gin.Context) error {
+	if c.Query("strict") != "true" {
+		return nil
+	}
+
+	var duplicates []string
+	for name, values := range c.Request.URL.Query() {
+		if len(values) > 1 && !multiValueParams[name] {
+			duplicates = append(duplicates, name)
+		}
+	}
+	if len(duplicates) == 0 {
+		return nil
+	}
+
+	sort.Strings(duplicates)
+	return fmt.Errorf("duplicate query parameter: %s may only be given once", strings.Join(duplicates, ", "))
+}
+
+// queryList collects every value of a multi-value parameter, accepting both
+// repeated parameters and comma-separated values; blank entries are dropped.
+func queryList(c *gin.Context, name string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(name) {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
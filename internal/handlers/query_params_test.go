@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRSSHandler_GetTop5_RepeatedFilterFirstWins(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+
+	w := doTop5Request(handler, url.Values{"filter": {"Headline 2", "Headline 5"}})
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response HeadlinesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Headlines, 1)
+	assert.Equal(t, "Headline 2", response.Headlines[0].Title)
+}
+
+func TestRSSHandler_RepeatedFilterStrict(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+	query := url.Values{"filter": {"Headline 2", "Headline 5"}, "strict": {"true"}}
+
+	top5 := doTop5Request(handler, query)
+
+	exportQuery := url.Values{"format": {"json"}}
+	for key, values := range query {
+		exportQuery[key] = values
+	}
+	export := doExportRequest(handler, exportQuery.Encode())
+
+	for _, w := range []*httptest.ResponseRecorder{top5, export} {
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "duplicate query parameter: filter may only be given once", response.Error)
+	}
+}
+
+func TestQueryList_RepeatedCategory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/?category=Politik&category=Sport,%20Kultur&category=&strict=true", nil)
+
+	assert.Equal(t, []string{"Politik", "Sport", "Kultur"}, queryList(c, "category"))
+	assert.NoError(t, checkDuplicateParams(c), "multi-value params are allowed in strict mode")
+}
+
+func TestRSSHandler_GetTop5_StrictLimit(t *testing.T) {
+	handler := newExportTestHandler(t, generateLargeRSSFeed(20))
+
+	tests := []struct {
+		name           string
+		query          url.Values
+		expectedStatus int
+		expectedCount  int
+	}{
+		{name: "strict over max", query: url.Values{"limit": {"500"}, "strict": {"true"}}, expectedStatus: http.StatusBadRequest},
+		{name: "strict just over max", query: url.Values{"limit": {"201"}, "strict": {"true"}}, expectedStatus: http.StatusBadRequest},
+		{name: "strict max", query: url.Values{"limit": {"200"}, "strict": {"true"}}, expectedStatus: http.StatusOK, expectedCount: 20},
+		{name: "strict non-numeric", query: url.Values{"limit": {"abc"}, "strict": {"true"}}, expectedStatus: http.StatusBadRequest},
+		{name: "strict zero", query: url.Values{"limit": {"0"}, "strict": {"true"}}, expectedStatus: http.StatusBadRequest},
+		{name: "strict valid", query: url.Values{"limit": {"10"}, "strict": {"true"}}, expectedStatus: http.StatusOK, expectedCount: 10},
+		{name: "lenient over max is capped", query: url.Values{"limit": {"500"}}, expectedStatus: http.StatusOK, expectedCount: 20},
+		{name: "lenient non-numeric uses default", query: url.Values{"limit": {"abc"}}, expectedStatus: http.StatusOK, expectedCount: defaultReturnItems},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doTop5Request(handler, tt.query)
+			require.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				var response ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "limit must be between 1 and 200", response.Error)
+				assert.Equal(t, CodeBadRequest, response.Code)
+				return
+			}
+
+			var response HeadlinesResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Len(t, response.Headlines, tt.expectedCount)
+		})
+	}
+}
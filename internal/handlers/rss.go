@@ -1,11 +1,11 @@
 package handlers
 
 import (
-	"bytes"
 	"context"
-	"encoding/csv"
+	"errors"
 	"fmt"
-	"io"
+	"math/rand"
+	"net"
 	"net/http"
 	"regexp"
 	"strconv"
@@ -16,16 +16,11 @@ import (
 	"github.com/f00b455/golang-template/internal/config"
 	"github.com/f00b455/golang-template/pkg/shared"
 	"github.com/gin-gonic/gin"
+	"golang.org/x/sync/singleflight"
 )
 
 const (
-	cacheTTL       = 5 * time.Minute
-	requestTimeout = 2 * time.Second
-	// maxFetchItems defines how many RSS items to fetch from the feed.
-	// We fetch 250 items to ensure we have enough data for the 200 item limit,
-	// while accounting for potential filtering. This provides a buffer for
-	// filtered results while keeping memory usage manageable.
-	maxFetchItems = 250
+	cacheTTL = 5 * time.Minute
 	// maxReturnItems defines the maximum number of items to return in the API response.
 	// Increased to 200 to support displaying more news items in the terminal UI.
 	maxReturnItems = 200
@@ -36,36 +31,84 @@ const (
 	maxFilterLength = 100
 	// maxExportItems is the maximum number of items allowed in export to prevent resource exhaustion
 	maxExportItems = 1000
+	// defaultSourceKey labels headlines whose feed has no channel title.
+	defaultSourceKey = "SPIEGEL"
 )
 
 // RSSHandler handles RSS-related requests.
 type RSSHandler struct {
-	cfg         *config.Config
-	cache       *cacheEntry
-	multiCache  *multiCacheEntry
+	cfg        *config.Config
+	cache      *cacheEntry
+	multiCache *multiCacheEntry
+	// sourceCache holds the headlines of registered sources other than the built-in one
+	sourceCache map[string]*multiCacheEntry
+	sources     *sourceRegistry
 	mu          sync.RWMutex
 	httpClient  *http.Client
-	fetchMutex  sync.Mutex // Prevents concurrent RSS fetches
+	// sourceClient fetches imported sources; NewRSSHandler gives it a transport
+	// that only connects to public addresses
+	sourceClient *http.Client
+	// lookupIP resolves the hosts of imported feeds before they are registered
+	lookupIP func(ctx context.Context, host string) ([]net.IP, error)
+	// lastFetches keeps the last successful upstream fetch per source; unlike the
+	// caches it survives ResetCache, so it can rate-limit fetches of each feed
+	lastFetches map[string]*multiCacheEntry
+	// fetchGroup collapses concurrent cache misses into a single upstream fetch per source
+	fetchGroup singleflight.Group
+	// fetchSlots is a semaphore bounding the number of concurrent upstream fetches
+	fetchSlots chan struct{}
+	// exportSlots is a semaphore bounding the number of concurrent exports
+	exportSlots chan struct{}
+	breaker     *circuitBreaker
+	// responses caches finished top5 responses by request shape
+	responses *responseLRU
+	// refreshInterval is how often StartBackgroundRefresh refetches the feed
+	refreshInterval time.Duration
+	// newRand returns the random source GetRandom picks with
+	newRand func() *rand.Rand
 	// Compiled regex patterns for better performance
-	itemRegex    *regexp.Regexp
-	titleRegex   *regexp.Regexp
-	linkRegex    *regexp.Regexp
-	pubDateRegex *regexp.Regexp
+	itemRegex     *regexp.Regexp
+	titleRegex    *regexp.Regexp
+	linkRegex     *regexp.Regexp
+	pubDateRegex  *regexp.Regexp
+	descRegex     *regexp.Regexp
+	categoryRegex *regexp.Regexp
+	imageTagRegex *regexp.Regexp
+	attrRegex     *regexp.Regexp
+	// htmlTagRegex matches the inline HTML tags feeds leave in titles; other
+	// angle-bracketed text such as "<live>" is part of the title
+	htmlTagRegex *regexp.Regexp
 }
 
 type cacheEntry struct {
-	data      *shared.RssHeadline
-	timestamp time.Time
+	data *shared.RssHeadline
+	// totalCount is the number of headlines in the feed the latest headline was taken from
+	totalCount int
+	timestamp  time.Time
 }
 
 type multiCacheEntry struct {
 	data      []shared.RssHeadline
 	timestamp time.Time
+	// validators are sent when the feed is fetched again, so an unchanged
+	// feed only renews timestamp
+	validators feedValidators
+	// rawItems holds the original <item> XML by link for raw=true requests;
+	// only the built-in feed keeps it, and headlines never carry it in the cache
+	rawItems map[string]string
 }
 
 // ErrorResponse represents an error response.
 type ErrorResponse struct {
 	Error string `json:"error" example:"Unable to fetch RSS feed"`
+	Code  string `json:"code,omitempty" example:"UPSTREAM_TIMEOUT"`
+}
+
+// LatestHeadlineResponse represents the latest headline together with the number
+// of headlines available; the headline fields stay at the top level.
+type LatestHeadlineResponse struct {
+	shared.RssHeadline
+	TotalCount int `json:"totalCount" example:"42"`
 }
 
 // HeadlinesResponse represents the response for multiple headlines.
@@ -74,278 +117,289 @@ type HeadlinesResponse struct {
 	TotalCount int                  `json:"totalCount,omitempty"`
 }
 
-// NewRSSHandler creates a new RSSHandler.
-func NewRSSHandler() *RSSHandler {
-	// Create HTTP client with optimized transport settings
-	transport := &http.Transport{
-		MaxIdleConns:        100,
-		MaxIdleConnsPerHost: 10,
-		IdleConnTimeout:     90 * time.Second,
-	}
-
-	return &RSSHandler{
-		cfg:          config.Load(),
-		cache:        &cacheEntry{},
-		multiCache:   &multiCacheEntry{},
-		httpClient:   &http.Client{Timeout: requestTimeout, Transport: transport},
-		itemRegex:    regexp.MustCompile(`<item[^>]*>([\s\S]*?)</item>`),
-		titleRegex:   regexp.MustCompile(`<title>(.*?)</title>`),
-		linkRegex:    regexp.MustCompile(`<link>(.*?)</link>`),
-		pubDateRegex: regexp.MustCompile(`<pubDate>([^<]+)</pubDate>`),
+// RSSHandlerOption customizes an RSSHandler at construction.
+type RSSHandlerOption func(*rssHandlerOptions)
+
+type rssHandlerOptions struct {
+	warmCache bool
+}
+
+// WithWarmCache makes the handler fetch the feed once in the background right
+// after construction, so the first request does not wait for the upstream.
+func WithWarmCache() RSSHandlerOption {
+	return func(o *rssHandlerOptions) {
+		o.warmCache = true
 	}
 }
 
+// NewRSSHandler creates a new RSSHandler that fetches through the shared,
+// connection-pooling HTTP client.
+func NewRSSHandler(opts ...RSSHandlerOption) *RSSHandler {
+	cfg := config.Load()
+	h := newRSSHandler(cfg, sharedHTTPClient(cfg), opts...)
+	h.sourceClient = sharedSourceClient(cfg)
+	return h
+}
+
 // NewRSSHandlerWithClient creates a new RSSHandler with a custom HTTP client (for testing).
-func NewRSSHandlerWithClient(client *http.Client) *RSSHandler {
-	return &RSSHandler{
-		cfg:          config.Load(),
-		cache:        &cacheEntry{},
-		multiCache:   &multiCacheEntry{},
-		httpClient:   client,
-		itemRegex:    regexp.MustCompile(`<item[^>]*>([\s\S]*?)</item>`),
-		titleRegex:   regexp.MustCompile(`<title>(.*?)</title>`),
-		linkRegex:    regexp.MustCompile(`<link>(.*?)</link>`),
-		pubDateRegex: regexp.MustCompile(`<pubDate>([^<]+)</pubDate>`),
-	}
+// The client fetches every feed, including imported sources.
+func NewRSSHandlerWithClient(client *http.Client, opts ...RSSHandlerOption) *RSSHandler {
+	return newRSSHandler(config.Load(), client, opts...)
+}
+
+func newRSSHandler(cfg *config.Config, client *http.Client, opts ...RSSHandlerOption) *RSSHandler {
+	var options rssHandlerOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	h := &RSSHandler{
+		cfg:             cfg,
+		cache:           &cacheEntry{},
+		multiCache:      &multiCacheEntry{},
+		sourceCache:     make(map[string]*multiCacheEntry),
+		lastFetches:     make(map[string]*multiCacheEntry),
+		sources:         newSourceRegistry(),
+		httpClient:      client,
+		sourceClient:    client,
+		lookupIP:        lookupIP,
+		exportSlots:     make(chan struct{}, cfg.MaxConcurrentExports),
+		fetchSlots:      make(chan struct{}, cfg.RSSMaxConcurrentFetches),
+		breaker:         newCircuitBreaker(cfg.RSSBreakerThreshold, cfg.RSSBreakerCooldown),
+		responses:       newResponseLRU(cfg.RSSResponseCacheSize, cfg.RSSResponseCacheTTL),
+		refreshInterval: cacheTTL,
+		newRand:         newSeededRand,
+		itemRegex:       regexp.MustCompile(`<item[^>]*>([\s\S]*?)</item>`),
+		titleRegex:      regexp.MustCompile(`<title>(.*?)</title>`),
+		linkRegex:       regexp.MustCompile(`<link>(.*?)</link>`),
+		pubDateRegex:    regexp.MustCompile(`<pubDate>([^<]+)</pubDate>`),
+		descRegex:       regexp.MustCompile(`<description>([\s\S]*?)</description>`),
+		categoryRegex:   regexp.MustCompile(`<category[^>]*>([\s\S]*?)</category>`),
+		imageTagRegex:   regexp.MustCompile(`<(media:thumbnail|media:content|enclosure)\b([^>]*)>`),
+		attrRegex:       regexp.MustCompile(`([\w:-]+)\s*=\s*(?:"([^"]*)"|'([^']*)')`),
+		htmlTagRegex:    regexp.MustCompile(`(?i)</?(?:a|abbr|b|br|cite|div|em|font|i|mark|p|q|s|small|span|strong|sub|sup|u)\b[^<>]*>`),
+	}
+	if options.warmCache {
+		go h.warmCache()
+	}
+	return h
 }
 
 // GetLatest handles GET /api/rss/spiegel/latest
 // @Summary      Get latest SPIEGEL RSS headline
-// @Description  Fetches the most recent headline from SPIEGEL RSS feed
+// @Description  Fetches the most recent headline from SPIEGEL RSS feed. When the feed fails, an expired cached headline is served with a `Warning: 110` header.
 // @Tags         rss
 // @Accept       json
 // @Produce      json
-// @Success      200  {object}  shared.RssHeadline
+// @Success      200  {object}  LatestHeadlineResponse
 // @Failure      503  {object}  ErrorResponse
+// @Failure      504  {object}  ErrorResponse
 // @Router       /rss/spiegel/latest [get]
 func (h *RSSHandler) GetLatest(c *gin.Context) {
 	h.mu.RLock()
 	if h.cache.data != nil && time.Since(h.cache.timestamp) < cacheTTL {
-		headline := *h.cache.data
+		response := LatestHeadlineResponse{RssHeadline: *h.cache.data, TotalCount: h.cache.totalCount}
 		h.mu.RUnlock()
-		c.JSON(http.StatusOK, headline)
+		c.JSON(http.StatusOK, response)
 		return
 	}
 	h.mu.RUnlock()
 
-	headline, err := h.fetchLatestHeadline()
+	headline, totalCount, err := h.fetchLatestHeadline(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
-			Error: "Unable to fetch RSS feed",
-		})
-		return
-	}
-
-	if headline == nil {
-		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
-			Error: "Unable to fetch RSS feed",
-		})
+		h.mu.RLock()
+		stale := h.cache
+		h.mu.RUnlock()
+		if stale.data == nil {
+			respondUpstreamError(c, err)
+			return
+		}
+		respondStale(c, err)
+		c.JSON(http.StatusOK, LatestHeadlineResponse{RssHeadline: *stale.data, TotalCount: stale.totalCount})
 		return
 	}
 
 	h.mu.Lock()
 	h.cache = &cacheEntry{
-		data:      headline,
-		timestamp: time.Now(),
+		data:       headline,
+		totalCount: totalCount,
+		timestamp:  time.Now(),
 	}
 	h.mu.Unlock()
 
-	c.JSON(http.StatusOK, *headline)
+	c.JSON(http.StatusOK, LatestHeadlineResponse{RssHeadline: *headline, TotalCount: totalCount})
 }
 
 // GetTop5 handles GET /api/rss/spiegel/top5
 // @Summary      Get top N SPIEGEL RSS headlines
-// @Description  Fetches the top N headlines from SPIEGEL RSS feed (max 200)
+// @Description  Fetches the top N headlines from SPIEGEL RSS feed (max 200). When the feed fails, expired cached headlines are served with a `Warning: 110` header.
 // @Tags         rss
 // @Accept       json
 // @Produce      json
 // @Param        limit    query     int     false  "Number of headlines to fetch (1-200)" minimum(1) maximum(200) default(5)
 // @Param        filter   query     string  false  "Filter headlines by keyword"
+// @Param        category query     string  false  "Only headlines with one of these categories (exact, case-insensitive; repeat or comma-separate)"
+// @Param        fuzzy    query     bool    false  "Match the filter against title words allowing typos (Levenshtein distance)"
+// @Param        from     query     string  false  "Only headlines published at or after this RFC3339 date"
+// @Param        to       query     string  false  "Only headlines published at or before this RFC3339 date"
+// @Param        raw      query     bool    false  "Include the raw <item> XML of each headline"
+// @Param        strict   query     bool    false  "Reject repeated query parameters and out-of-range limits with 400"
+// @Param        canonicalLinks query bool false  "Strip tracking parameters and fragments from links (original kept in rawLink)"
+// @Param        callback query     string  false  "Wrap the response in callback(...) as application/javascript (JSONP); must be a JavaScript identifier"
 // @Success      200      {object}  HeadlinesResponse
 // @Failure      400      {object}  ErrorResponse
 // @Failure      503      {object}  ErrorResponse
+// @Failure      504      {object}  ErrorResponse
 // @Router       /rss/spiegel/top5 [get]
 func (h *RSSHandler) GetTop5(c *gin.Context) {
-	limit := h.parseLimit(c)
+	if err := checkDuplicateParams(c); err != nil {
+		respondBadRequest(c, err)
+		return
+	}
+
+	limit, err := h.parseLimit(c)
+	if err != nil {
+		respondBadRequest(c, err)
+		return
+	}
 	filterKeyword := c.Query("filter")
 
-	// Validate filter parameter
+	// Validate filter parameters
 	if err := h.validateFilter(filterKeyword); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: err.Error(),
-		})
+		respondBadRequest(c, err)
 		return
 	}
+	for _, category := range queryList(c, "category") {
+		if err := h.validateFilter(category); err != nil {
+			respondBadRequest(c, err)
+			return
+		}
+	}
 
-	// Try to get headlines from cache
+	publishedRange, err := parseDateRange(c)
+	if err != nil {
+		respondBadRequest(c, err)
+		return
+	}
+	if err := validateCallback(c.Query("callback")); err != nil {
+		respondBadRequest(c, err)
+		return
+	}
+
+	// Try to get headlines from cache. Cached responses are only consulted
+	// while the headlines are fresh, so an expired cache still goes through
+	// the fetch and stale-while-error path below.
+	key, cacheable := responseCacheKey(c, limit, filterKeyword)
 	headlines, totalCount := h.getCachedHeadlines()
+	if headlines != nil && cacheable {
+		if cached, ok := h.responses.get(key); ok {
+			c.JSONP(http.StatusOK, cached)
+			return
+		}
+	}
 	if headlines == nil {
 		// Cache miss - fetch from RSS feed
-		var err error
-		headlines, err = h.fetchAndCacheHeadlines()
+		headlines, err = h.fetchAndCacheHeadlines(c.Request.Context())
 		if err != nil {
-			c.JSON(http.StatusServiceUnavailable, ErrorResponse{
-				Error: "Unable to fetch RSS feed",
+			stale := h.staleHeadlines()
+			if stale == nil {
+				respondUpstreamError(c, err)
+				return
+			}
+			respondStale(c, err)
+			c.JSONP(http.StatusOK, HeadlinesResponse{
+				Headlines:  h.selectHeadlines(c, stale, publishedRange, filterKeyword, limit),
+				TotalCount: len(stale),
 			})
 			return
 		}
 		totalCount = len(headlines)
 	}
 
-	// Apply filter and limit
-	headlines = h.applyFilterAndLimit(headlines, filterKeyword, limit)
-
-	c.JSON(http.StatusOK, HeadlinesResponse{
-		Headlines:  headlines,
+	response := HeadlinesResponse{
+		Headlines:  h.selectHeadlines(c, headlines, publishedRange, filterKeyword, limit),
 		TotalCount: totalCount,
-	})
-}
-
-func (h *RSSHandler) fetchLatestHeadline() (*shared.RssHeadline, error) {
-	rssText, err := h.fetchRSSFeed()
-	if err != nil {
-		return nil, err
 	}
-
-	// Find first item in RSS feed using pre-compiled regex
-	matches := h.itemRegex.FindStringSubmatch(rssText)
-	if len(matches) < 2 {
-		return nil, fmt.Errorf("no RSS items found")
-	}
-
-	return h.parseRSSItem(matches[1])
-}
-
-func (h *RSSHandler) fetchMultipleHeadlines(limit int) ([]shared.RssHeadline, error) {
-	rssText, err := h.fetchRSSFeed()
-	if err != nil {
-		return nil, err
+	if cacheable {
+		h.responses.add(key, response)
 	}
-
-	return h.parseMultipleRSSItems(rssText, limit), nil
+	// Without a callback parameter JSONP writes plain JSON
+	c.JSONP(http.StatusOK, response)
 }
 
-func (h *RSSHandler) fetchRSSFeed() (string, error) {
-	// Use context with timeout for better control
-	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-	defer cancel()
-
-	req, err := http.NewRequestWithContext(ctx, "GET", h.cfg.SpiegelRSSURL, nil)
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Accept", "application/rss+xml, application/xml, text/xml")
-	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; Golang-Template/1.0)")
-
-	resp, err := h.httpClient.Do(req)
-	if err != nil {
-		if ctx.Err() == context.DeadlineExceeded {
-			return "", fmt.Errorf("request timeout after %v", requestTimeout)
+// responseCacheKey returns the response cache key for a top5 request and whether
+// the response may be cached at all; requests using the date range, fuzzy, raw
+// or canonicalLinks parameters are always rebuilt.
+func responseCacheKey(c *gin.Context, limit int, filter string) (responseKey, bool) {
+	for _, param := range []string{"from", "to", "fuzzy", "raw", "canonicalLinks"} {
+		if c.Query(param) != "" {
+			return responseKey{}, false
 		}
-		return "", fmt.Errorf("failed to fetch RSS feed: %w", err)
 	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("RSS fetch failed with status code %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	return string(body), nil
+	return responseKey{
+		source:   defaultSourceKey,
+		limit:    limit,
+		filter:   strings.ToLower(filter),
+		category: strings.ToLower(strings.Join(queryList(c, "category"), ",")),
+	}, true
 }
 
-func (h *RSSHandler) parseRSSItem(itemText string) (*shared.RssHeadline, error) {
-	// Use pre-compiled regex patterns for better performance
-	titleMatches := h.titleRegex.FindStringSubmatch(itemText)
-	linkMatches := h.linkRegex.FindStringSubmatch(itemText)
-
-	if len(titleMatches) < 2 || len(linkMatches) < 2 {
-		return nil, fmt.Errorf("required RSS fields not found")
-	}
-
-	title := h.cleanCDATA(titleMatches[1])
-	link := h.cleanCDATA(linkMatches[1])
-
-	publishedAt := time.Now().Format(time.RFC3339)
-	if pubDateMatches := h.pubDateRegex.FindStringSubmatch(itemText); len(pubDateMatches) > 1 {
-		if parsed, err := time.Parse(time.RFC1123Z, pubDateMatches[1]); err == nil {
-			publishedAt = parsed.Format(time.RFC3339)
-		}
+// selectHeadlines applies the date range, category, filter (fuzzy when fuzzy=true) and
+// limit, then shapes the result according to the raw and canonicalLinks parameters.
+func (h *RSSHandler) selectHeadlines(c *gin.Context, headlines []shared.RssHeadline, publishedRange dateRange, filter string, limit int) []shared.RssHeadline {
+	headlines = h.filterByDateRange(headlines, publishedRange)
+	headlines = filterByCategory(headlines, queryList(c, "category"))
+	if c.Query("fuzzy") == "true" {
+		headlines = h.fuzzyFilterHeadlines(headlines, filter)
+		filter = ""
 	}
-
-	return &shared.RssHeadline{
-		Title:       title,
-		Link:        link,
-		PublishedAt: publishedAt,
-		Source:      "SPIEGEL",
-	}, nil
-}
-
-func (h *RSSHandler) parseMultipleRSSItems(rssText string, limit int) []shared.RssHeadline {
-	matches := h.extractRSSItems(rssText, limit)
-	return h.processRSSMatches(matches, limit)
-}
-
-// extractRSSItems finds RSS item matches in the text
-func (h *RSSHandler) extractRSSItems(rssText string, limit int) [][]string {
-	// Use pre-compiled regex for better performance
-	maxMatches := limit + (limit / 5) // Add 20% buffer for invalid items
-	return h.itemRegex.FindAllStringSubmatch(rssText, maxMatches)
-}
-
-// processRSSMatches converts regex matches to RssHeadline objects
-func (h *RSSHandler) processRSSMatches(matches [][]string, limit int) []shared.RssHeadline {
-	// Pre-allocate with estimated capacity
-	estimatedCapacity := limit
-	if len(matches) < limit {
-		estimatedCapacity = len(matches)
+	headlines = h.applyFilterAndLimit(headlines, filter, limit)
+	if c.Query("raw") == "true" {
+		headlines = h.withRawXML(headlines)
 	}
-	headlines := make([]shared.RssHeadline, 0, estimatedCapacity)
-
-	for i := 0; i < len(matches) && len(headlines) < limit; i++ {
-		if len(matches[i]) < 2 {
-			continue
-		}
-
-		if headline := h.parseItemSafe(matches[i][1]); headline != nil {
-			headlines = append(headlines, *headline)
-		}
+	if c.Query("canonicalLinks") == "true" {
+		headlines = withCanonicalLinks(headlines)
 	}
-
 	return headlines
 }
 
-// parseItemSafe safely parses an RSS item, returning nil on error
-func (h *RSSHandler) parseItemSafe(itemText string) *shared.RssHeadline {
-	headline, err := h.parseRSSItem(itemText)
-	if err != nil {
-		return nil
+// withRawXML returns a copy of headlines carrying their original <item> XML
+// from the cached feed; headlines it does not know keep an empty Raw.
+func (h *RSSHandler) withRawXML(headlines []shared.RssHeadline) []shared.RssHeadline {
+	h.mu.RLock()
+	rawItems := h.multiCache.rawItems
+	h.mu.RUnlock()
+
+	withRaw := make([]shared.RssHeadline, len(headlines))
+	for i, headline := range headlines {
+		headline.Raw = rawItems[headline.Link]
+		withRaw[i] = headline
 	}
-	return headline
+	return withRaw
 }
 
-func (h *RSSHandler) cleanCDATA(text string) string {
-	text = strings.ReplaceAll(text, "<![CDATA[", "")
-	text = strings.ReplaceAll(text, "]]>", "")
-	return strings.TrimSpace(text)
+// parseLimit extracts and validates the limit parameter from the request.
+// Invalid values fall back to the default and large values are capped, unless
+// strict=true, in which case they are reported as an error.
+func (h *RSSHandler) parseLimit(c *gin.Context) (int, error) {
+	return parseCountParam(c, "limit", defaultReturnItems)
 }
 
-// parseLimit extracts and validates the limit parameter from the request.
-func (h *RSSHandler) parseLimit(c *gin.Context) int {
-	limitStr := c.DefaultQuery("limit", strconv.Itoa(defaultReturnItems))
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 {
-		return defaultReturnItems
+// parseCountParam reads an item count between 1 and maxReturnItems from the
+// query parameter name, with parseLimit's fallback and strict-mode rules.
+func parseCountParam(c *gin.Context, name string, defaultValue int) (int, error) {
+	value, err := strconv.Atoi(c.DefaultQuery(name, strconv.Itoa(defaultValue)))
+	valid := err == nil && value >= 1 && value <= maxReturnItems
+	if !valid && c.Query("strict") == "true" {
+		return 0, fmt.Errorf("%s must be between 1 and %d", name, maxReturnItems)
+	}
+	if err != nil || value < 1 {
+		return defaultValue, nil
 	}
-	if limit > maxReturnItems {
-		return maxReturnItems
+	if value > maxReturnItems {
+		return maxReturnItems, nil
 	}
-	return limit
+	return value, nil
 }
 
 // validateFilter validates the filter parameter.
@@ -370,38 +424,124 @@ func (h *RSSHandler) getCachedHeadlines() ([]shared.RssHeadline, int) {
 	return nil, 0
 }
 
+// staleHeadlines returns a copy of the cached headlines however old they are,
+// or nil when nothing was ever cached (or the cache was reset).
+func (h *RSSHandler) staleHeadlines() []shared.RssHeadline {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if len(h.multiCache.data) == 0 {
+		return nil
+	}
+	return copyHeadlines(h.multiCache.data)
+}
+
 // fetchAndCacheHeadlines fetches headlines from RSS feed and updates the cache.
-func (h *RSSHandler) fetchAndCacheHeadlines() ([]shared.RssHeadline, error) {
-	// Prevent concurrent RSS fetches to avoid overwhelming the server
-	h.fetchMutex.Lock()
-	defer h.fetchMutex.Unlock()
+// Concurrent callers share a single upstream fetch, bound to the context of the
+// caller that started it, and each receive their own copy. A caller whose ctx
+// ends stops waiting right away. Within RSSMinFetchInterval of the last upstream
+// fetch, the cache is refilled from that fetch instead of fetching again.
+func (h *RSSHandler) fetchAndCacheHeadlines(ctx context.Context) ([]shared.RssHeadline, error) {
+	result, err := h.sharedFetch(ctx, defaultSourceKey, func() (interface{}, error) {
+		// A fetch that finished just before this one started has already filled the cache
+		if headlines, _ := h.getCachedHeadlines(); headlines != nil {
+			return headlines, nil
+		}
+		if last := h.recentFetch(defaultSourceKey); last != nil {
+			h.mu.Lock()
+			h.multiCache = last
+			h.mu.Unlock()
+			return last.data, nil
+		}
+		return h.fetchIntoCache(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy so callers sharing the fetch never alias each other's or the cache's slice
+	return copyHeadlines(result.([]shared.RssHeadline)), nil
+}
 
-	// Double-check cache after acquiring lock
-	headlines, _ := h.getCachedHeadlines()
-	if headlines != nil {
-		return headlines, nil
+// sharedFetch runs fetch through fetchGroup under key and waits for its result
+// until ctx ends. Joining a fetch whose own caller went away yields that
+// caller's context error; the fetch is then started again for this caller.
+func (h *RSSHandler) sharedFetch(ctx context.Context, key string, fetch func() (interface{}, error)) (interface{}, error) {
+	for {
+		select {
+		case result := <-h.fetchGroup.DoChan(key, fetch):
+			if isContextError(result.Err) && ctx.Err() == nil {
+				continue
+			}
+			return result.Val, result.Err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
 	}
+}
+
+// isContextError reports whether err comes from a cancelled or expired context.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// fetchIntoCache fetches the feed and replaces the cached headlines with the result.
+// Cached responses built from the previous headlines are dropped. When the
+// upstream reports the feed unchanged (304), the cached headlines are kept
+// without parsing anything and only their timestamp is renewed.
+func (h *RSSHandler) fetchIntoCache(ctx context.Context) ([]shared.RssHeadline, error) {
+	h.mu.RLock()
+	cached := h.multiCache
+	h.mu.RUnlock()
 
-	// Fetch headlines from RSS feed
-	headlines, err := h.fetchMultipleHeadlines(maxFetchItems)
-	if err != nil || len(headlines) == 0 {
+	var validators feedValidators
+	if len(cached.data) > 0 {
+		validators = cached.validators
+	}
+	result, err := h.fetchRSSFeedIfModified(ctx, validators)
+	if err != nil {
 		return nil, err
 	}
+	if result.notModified {
+		h.mu.Lock()
+		h.multiCache = &multiCacheEntry{data: cached.data, timestamp: time.Now(), validators: cached.validators, rawItems: cached.rawItems}
+		h.lastFetches[defaultSourceKey] = h.multiCache
+		h.mu.Unlock()
+		return cached.data, nil
+	}
 
-	// Make a copy to avoid data races when reading from cache
-	headlinesCopy := make([]shared.RssHeadline, len(headlines))
-	copy(headlinesCopy, headlines)
+	headlines, rawItems := h.parseItemsWithRaw(result.body, h.cfg.RSSMaxFetchItems)
+	if len(headlines) == 0 {
+		return nil, ErrParse
+	}
 
 	h.mu.Lock()
 	h.multiCache = &multiCacheEntry{
-		data:      headlinesCopy,
-		timestamp: time.Now(),
+		data:       headlines,
+		timestamp:  time.Now(),
+		validators: result.validators,
+		rawItems:   rawItems,
 	}
+	h.lastFetches[defaultSourceKey] = h.multiCache
 	h.mu.Unlock()
+	h.responses.purge()
 
 	return headlines, nil
 }
 
+// recentFetch returns the last upstream fetch of the source under key if it
+// happened less than RSSMinFetchInterval ago, or nil when the feed may be fetched.
+func (h *RSSHandler) recentFetch(key string) *multiCacheEntry {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	last := h.lastFetches[key]
+	if last == nil || time.Since(last.timestamp) >= h.cfg.RSSMinFetchInterval {
+		return nil
+	}
+	return last
+}
+
 // applyFilterAndLimit applies the filter keyword and limit to headlines.
 func (h *RSSHandler) applyFilterAndLimit(headlines []shared.RssHeadline, filter string, limit int) []shared.RssHeadline {
 	// Early return for common case
@@ -422,270 +562,64 @@ func (h *RSSHandler) applyFilterAndLimit(headlines []shared.RssHeadline, filter
 	return headlines
 }
 
-// filterHeadlines filters headlines based on a keyword (case-insensitive).
-func (h *RSSHandler) filterHeadlines(headlines []shared.RssHeadline, keyword string) []shared.RssHeadline {
-	if keyword == "" {
+// filterByCategory keeps the headlines having any of categories (exact,
+// case-insensitive match). No categories keeps all headlines.
+func filterByCategory(headlines []shared.RssHeadline, categories []string) []shared.RssHeadline {
+	if len(categories) == 0 {
 		return headlines
 	}
 
-	keyword = strings.ToLower(keyword)
-	// Pre-allocate with estimated capacity (assuming ~30% match rate)
-	estimatedCapacity := len(headlines) / 3
-	if estimatedCapacity < 1 {
-		estimatedCapacity = 1
-	}
-	filtered := make([]shared.RssHeadline, 0, estimatedCapacity)
-
+	filtered := make([]shared.RssHeadline, 0, len(headlines))
 	for _, headline := range headlines {
-		if strings.Contains(strings.ToLower(headline.Title), keyword) {
+		if hasAnyCategory(headline, categories) {
 			filtered = append(filtered, headline)
 		}
 	}
-
 	return filtered
 }
 
-// ExportHeadlines handles GET /api/rss/spiegel/export
-// @Summary      Export SPIEGEL RSS headlines
-// @Description  Exports RSS headlines in CSV or JSON format
-// @Tags         rss
-// @Accept       json
-// @Produce      json
-// @Produce      text/csv
-// @Param        format   query     string  true   "Export format (json or csv)"
-// @Param        filter   query     string  false  "Filter headlines by keyword"
-// @Param        limit    query     int     false  "Number of headlines to export (1-1000)" minimum(1) maximum(1000)
-// @Success      200      {object}  object
-// @Failure      400      {object}  ErrorResponse
-// @Failure      503      {object}  ErrorResponse
-// @Router       /rss/spiegel/export [get]
-// validateExportFormat checks if the export format is valid
-func (h *RSSHandler) validateExportFormat(format string) error {
-	if format == "" {
-		return fmt.Errorf("missing format parameter")
-	}
-	if format != "json" && format != "csv" {
-		return fmt.Errorf("invalid format parameter: must be 'json' or 'csv'")
-	}
-	return nil
-}
-
-// prepareExportData fetches and filters headlines for export
-func (h *RSSHandler) prepareExportData(filterKeyword string, limit int) ([]shared.RssHeadline, error) {
-	headlines, _ := h.getCachedHeadlines()
-	if headlines == nil {
-		var err error
-		headlines, err = h.fetchAndCacheHeadlines()
-		if err != nil {
-			return nil, err
+// hasAnyCategory reports whether headline has one of categories (case-insensitive).
+func hasAnyCategory(headline shared.RssHeadline, categories []string) bool {
+	for _, candidate := range headline.Categories {
+		for _, category := range categories {
+			if strings.EqualFold(candidate, category) {
+				return true
+			}
 		}
 	}
-
-	// Apply filter
-	if filterKeyword != "" {
-		headlines = h.filterHeadlines(headlines, filterKeyword)
-	}
-
-	// Apply limit
-	if limit > 0 && len(headlines) > limit {
-		headlines = headlines[:limit]
-	}
-
-	return headlines, nil
-}
-
-// generateExportFilename creates a filename for export with optional filter
-func (h *RSSHandler) generateExportFilename(format, filter string) string {
-	timestamp := time.Now().Format("20060102_150405")
-	if filter != "" {
-		return fmt.Sprintf("rss_export_%s_%s.%s", filter, timestamp, format)
-	}
-	return fmt.Sprintf("rss_export_%s.%s", timestamp, format)
-}
-
-func (h *RSSHandler) ExportHeadlines(c *gin.Context) {
-	params, err := h.validateExportParams(c)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
-		return
-	}
-
-	headlines, err := h.prepareExportData(params.filter, params.limit)
-	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, ErrorResponse{Error: "Unable to fetch RSS feed"})
-		return
-	}
-
-	h.performExport(c, headlines, params)
-}
-
-// exportParams holds validated export parameters
-type exportParams struct {
-	format string
-	filter string
-	limit  int
-}
-
-// validateExportParams validates all export parameters
-func (h *RSSHandler) validateExportParams(c *gin.Context) (*exportParams, error) {
-	format := c.Query("format")
-	if err := h.validateExportFormat(format); err != nil {
-		return nil, err
-	}
-
-	filter := c.Query("filter")
-	if err := h.validateFilter(filter); err != nil {
-		return nil, err
-	}
-
-	limit, err := h.validateAndParseExportLimit(c)
-	if err != nil {
-		return nil, err
-	}
-
-	return &exportParams{
-		format: format,
-		filter: filter,
-		limit:  limit,
-	}, nil
-}
-
-// validateAndParseExportLimit validates and parses the export limit
-func (h *RSSHandler) validateAndParseExportLimit(c *gin.Context) (int, error) {
-	limitStr := c.Query("limit")
-	if limitStr == "" {
-		return maxExportItems, nil
-	}
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 {
-		return maxExportItems, nil
-	}
-
-	if limit > maxExportItems {
-		return 0, fmt.Errorf("limit exceeds maximum allowed value of %d", maxExportItems)
-	}
-
-	return limit, nil
+	return false
 }
 
-// performExport executes the actual export based on format
-func (h *RSSHandler) performExport(c *gin.Context, headlines []shared.RssHeadline, params *exportParams) {
-	filename := h.generateExportFilename(params.format, params.filter)
-
-	if params.format == "json" {
-		h.exportAsJSON(c, headlines, params.filter, filename)
-	} else {
-		h.exportAsCSV(c, headlines, filename)
-	}
-}
-
-func (h *RSSHandler) exportAsJSON(c *gin.Context, headlines []shared.RssHeadline, filter, filename string) {
-	response := struct {
-		ExportDate    string               `json:"export_date"`
-		TotalItems    int                  `json:"total_items"`
-		FilterApplied string               `json:"filter_applied,omitempty"`
-		Headlines     []shared.RssHeadline `json:"headlines"`
-	}{
-		ExportDate: time.Now().Format(time.RFC3339),
-		TotalItems: len(headlines),
-		Headlines:  headlines,
-	}
-
-	if filter != "" {
-		response.FilterApplied = filter
+// filterHeadlines filters headlines based on a keyword (case-insensitive).
+func (h *RSSHandler) filterHeadlines(headlines []shared.RssHeadline, keyword string) []shared.RssHeadline {
+	if keyword == "" {
+		return headlines
 	}
 
-	// Set security headers
-	c.Header("Content-Type", "application/json")
-	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
-	c.Header("X-Content-Type-Options", "nosniff")
-	c.Header("X-Frame-Options", "DENY")
-	c.Header("Content-Security-Policy", "default-src 'none'")
-	c.JSON(http.StatusOK, response)
-}
-
-func (h *RSSHandler) exportAsCSV(c *gin.Context, headlines []shared.RssHeadline, filename string) {
-	// Build CSV content in memory to calculate Content-Length
-	var buf bytes.Buffer
-	writer := csv.NewWriter(&buf)
-
-	// Write header
-	headers := []string{"Title", "Link", "Published_At", "Source"}
-	if err := writer.Write(headers); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to write CSV headers",
-		})
-		return
+	keyword = strings.ToLower(keyword)
+	// Pre-allocate with estimated capacity (assuming ~30% match rate)
+	estimatedCapacity := len(headlines) / 3
+	if estimatedCapacity < 1 {
+		estimatedCapacity = 1
 	}
+	filtered := make([]shared.RssHeadline, 0, estimatedCapacity)
 
-	// Write data rows with sanitization
 	for _, headline := range headlines {
-		row := []string{
-			h.sanitizeCSVField(headline.Title),
-			h.sanitizeCSVField(headline.Link),
-			h.sanitizeCSVField(headline.PublishedAt),
-			h.sanitizeCSVField(headline.Source),
-		}
-		if err := writer.Write(row); err != nil {
-			c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error: "Failed to write CSV row",
-			})
-			return
-		}
-	}
-
-	writer.Flush()
-
-	// Check for any errors in CSV writer
-	if err := writer.Error(); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to generate CSV",
-		})
-		return
-	}
-
-	// Set headers including Content-Length
-	c.Header("Content-Type", "text/csv; charset=utf-8")
-	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
-	c.Header("Content-Length", fmt.Sprintf("%d", buf.Len()))
-	c.Header("X-Content-Type-Options", "nosniff")
-	c.Header("X-Frame-Options", "DENY")
-	c.Header("Content-Security-Policy", "default-src 'none'")
-
-	// Write the response
-	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
-}
-
-
-// sanitizeCSVField protects against CSV injection by sanitizing field values.
-// It prefixes potentially dangerous characters with a single quote to neutralize
-// formula injection attempts.
-func (h *RSSHandler) sanitizeCSVField(field string) string {
-	if field == "" {
-		return field
-	}
-
-	// Check if the field starts with a potentially dangerous character
-	// These characters can trigger formula execution in spreadsheet applications
-	dangerousChars := []rune{'=', '+', '-', '@', '\t', '\r'}
-	firstChar := rune(field[0])
-
-	for _, dangerous := range dangerousChars {
-		if firstChar == dangerous {
-			// Prefix with single quote to neutralize formula injection
-			return "'" + field
+		if strings.Contains(strings.ToLower(headline.Title), keyword) {
+			filtered = append(filtered, headline)
 		}
 	}
 
-	return field
+	return filtered
 }
 
-// ResetCache resets both caches (for testing purposes).
+// ResetCache resets all caches (for testing purposes).
 func (h *RSSHandler) ResetCache() {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
 	h.cache = &cacheEntry{}
 	h.multiCache = &multiCacheEntry{}
+	h.sourceCache = make(map[string]*multiCacheEntry)
+	h.responses.purge()
 }
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/f00b455/golang-template/pkg/shared"
+	"github.com/gin-gonic/gin"
+)
+
+// LiveComparison reports how the cached headlines differ from a fresh upstream fetch.
+type LiveComparison struct {
+	CachedAt    string    `json:"cachedAt,omitempty"`
+	CachedCount int       `json:"cachedCount"`
+	LiveCount   int       `json:"liveCount"`
+	Delta       LiveDelta `json:"delta"`
+}
+
+// LiveDelta lists headlines, matched by link, that only appear on one side.
+type LiveDelta struct {
+	Added   []shared.RssHeadline `json:"added"`
+	Removed []shared.RssHeadline `json:"removed"`
+}
+
+// CompareLive handles GET /api/admin/compare-live
+// @Summary      Compare cached headlines with a live fetch
+// @Description  Fetches the feed without touching the cache and returns the headlines added or removed since the cache was filled
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  LiveComparison
+// @Failure      401  {object}  ErrorResponse
+// @Failure      503  {object}  ErrorResponse
+// @Failure      504  {object}  ErrorResponse
+// @Router       /admin/compare-live [get]
+func (h *RSSHandler) CompareLive(c *gin.Context) {
+	live, err := h.fetchMultipleHeadlines(c.Request.Context(), h.cfg.RSSMaxFetchItems)
+	if err != nil {
+		respondUpstreamError(c, err)
+		return
+	}
+
+	cached, cachedAt := h.cacheSnapshot()
+	comparison := LiveComparison{
+		CachedCount: len(cached),
+		LiveCount:   len(live),
+		Delta: LiveDelta{
+			Added:   headlinesMissingFrom(live, cached),
+			Removed: headlinesMissingFrom(cached, live),
+		},
+	}
+	if !cachedAt.IsZero() {
+		comparison.CachedAt = cachedAt.Format(time.RFC3339)
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
+
+// cacheSnapshot returns a copy of the cached headlines, even if expired, and when they were cached.
+func (h *RSSHandler) cacheSnapshot() ([]shared.RssHeadline, time.Time) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	headlines := make([]shared.RssHeadline, len(h.multiCache.data))
+	copy(headlines, h.multiCache.data)
+	return headlines, h.multiCache.timestamp
+}
+
+// headlinesMissingFrom returns the headlines in source whose link does not appear in other.
+func headlinesMissingFrom(source, other []shared.RssHeadline) []shared.RssHeadline {
+	links := make(map[string]struct{}, len(other))
+	for _, headline := range other {
+		links[headline.Link] = struct{}{}
+	}
+
+	missing := make([]shared.RssHeadline, 0)
+	for _, headline := range source {
+		if _, ok := links[headline.Link]; !ok {
+			missing = append(missing, headline)
+		}
+	}
+	return missing
+}
+
+// RefreshResponse reports the headlines cached by a manual refresh.
+type RefreshResponse struct {
+	Source string `json:"source" example:"spiegel"`
+	Items  int    `json:"items" example:"250"`
+}
+
+// RefreshSource handles POST /api/rss/{source}/refresh
+// @Summary      Refetch the headlines of a source
+// @Description  Fetches the feed of the source again, bypassing the cache and the minimum fetch interval. The old headlines stay cached, and are served as stale data, until the new fetch succeeds
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        source   path      string  true  "Feed source key (see /rss/sources)"
+// @Success      200      {object}  RefreshResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      503      {object}  ErrorResponse
+// @Failure      504      {object}  ErrorResponse
+// @Router       /rss/{source}/refresh [post]
+func (h *RSSHandler) RefreshSource(c *gin.Context) {
+	source, ok := h.sources.find(c.Param("source"))
+	if !ok {
+		respondUnknownSource(c, c.Param("source"))
+		return
+	}
+
+	h.expireSource(source)
+	headlines, err := h.headlinesForSource(c.Request.Context(), source)
+	if err != nil {
+		respondUpstreamError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, RefreshResponse{Source: source.Key, Items: len(headlines)})
+}
+
+// expireSource makes the next request for source fetch its feed, even within
+// RSSMinFetchInterval. The cached headlines are only marked as expired, so they
+// stay available as stale data until that fetch replaces them.
+func (h *RSSHandler) expireSource(source feedSource) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if source.URL != "" {
+		delete(h.lastFetches, source.Key)
+		if entry := h.sourceCache[source.Key]; entry != nil {
+			h.sourceCache[source.Key] = &multiCacheEntry{data: entry.data, validators: entry.validators}
+		}
+		return
+	}
+
+	delete(h.lastFetches, defaultSourceKey)
+	h.multiCache = &multiCacheEntry{data: h.multiCache.data, validators: h.multiCache.validators, rawItems: h.multiCache.rawItems}
+	h.cache = &cacheEntry{data: h.cache.data, totalCount: h.cache.totalCount}
+	h.responses.purge()
+}
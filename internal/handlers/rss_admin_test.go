@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/f00b455/golang-template/internal/testutil"
+	"github.com/f00b455/golang-template/pkg/shared"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRSSHandler_CompareLive_Delta(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponseFewItems)
+
+	cachedAt := time.Date(2023, 9, 24, 8, 0, 0, 0, time.UTC)
+	handler.multiCache = &multiCacheEntry{
+		data: []shared.RssHeadline{
+			{Title: "Headline 1", Link: "https://www.spiegel.de/1"},
+			{Title: "Old story", Link: "https://www.spiegel.de/old"},
+		},
+		timestamp: cachedAt,
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/admin/compare-live", nil)
+	handler.CompareLive(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var comparison LiveComparison
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &comparison))
+
+	assert.Equal(t, "2023-09-24T08:00:00Z", comparison.CachedAt)
+	assert.Equal(t, 2, comparison.CachedCount)
+	assert.Equal(t, 2, comparison.LiveCount)
+	require.Len(t, comparison.Delta.Added, 1)
+	assert.Equal(t, "https://www.spiegel.de/2", comparison.Delta.Added[0].Link)
+	require.Len(t, comparison.Delta.Removed, 1)
+	assert.Equal(t, "https://www.spiegel.de/old", comparison.Delta.Removed[0].Link)
+
+	// The live fetch must not replace the cached data.
+	assert.Equal(t, cachedAt, handler.multiCache.timestamp)
+	assert.Equal(t, "Old story", handler.multiCache.data[1].Title)
+}
+
+func TestRSSHandler_RefreshSource_ReplacesCachedHeadlines(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var feed atomic.Value
+	feed.Store(MockRSSResponseFewItems)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		_, _ = w.Write([]byte(feed.Load().(string)))
+	}))
+	defer server.Close()
+
+	handler := NewRSSHandler()
+	handler.cfg.SpiegelRSSURL = server.URL
+	handler.ResetCache()
+
+	router := gin.New()
+	router.GET("/api/rss/spiegel/top5", handler.GetTop5)
+	router.POST("/api/rss/:source/refresh", handler.RefreshSource)
+	top5 := func() HeadlinesResponse {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/rss/spiegel/top5", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var response HeadlinesResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	assert.Len(t, top5().Headlines, 2)
+
+	// The cache keeps serving the old feed until it is refreshed.
+	feed.Store(MockRSSResponse)
+	assert.Len(t, top5().Headlines, 2)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/rss/spiegel/refresh", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var refreshed RefreshResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &refreshed))
+	assert.Equal(t, RefreshResponse{Source: "spiegel", Items: 6}, refreshed)
+
+	assert.Len(t, top5().Headlines, 5)
+}
+
+func TestRSSHandler_RefreshSource_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := newExportTestHandler(t, MockRSSResponse)
+	router := gin.New()
+	router.POST("/api/rss/:source/refresh", handler.RefreshSource)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/rss/unknown/refresh", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	handler.cfg.SpiegelRSSURL = "http://invalid-url-that-does-not-exist.invalid"
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/rss/spiegel/refresh", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestRSSHandler_RefreshSource_FailureKeepsStaleHeadlines(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var failing atomic.Bool
+	handler := newRetryTestHandler(&http.Client{Transport: &testutil.MockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			if failing.Load() {
+				return statusResponse(req, http.StatusBadGateway, ""), nil
+			}
+			return statusResponse(req, http.StatusOK, MockRSSResponse), nil
+		},
+	}})
+	router := gin.New()
+	router.GET("/api/rss/spiegel/top5", handler.GetTop5)
+	router.POST("/api/rss/:source/refresh", handler.RefreshSource)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/rss/spiegel/top5", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	failing.Store(true)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/rss/spiegel/refresh", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/rss/spiegel/top5", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, staleWarning, w.Header().Get("Warning"), "the failed refresh left the old headlines to serve")
+	var response HeadlinesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Headlines, 5)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -42,8 +43,32 @@ func TestRSSHandler_GetLatest_Success(t *testing.T) {
 
 	assert.Equal(t, "Headline 1", response["title"])
 	assert.Equal(t, "https://www.spiegel.de/1", response["link"])
-	assert.Equal(t, "SPIEGEL", response["source"])
+	assert.Equal(t, "SPIEGEL ONLINE", response["source"])
 	assert.NotEmpty(t, response["publishedAt"])
+	assert.EqualValues(t, 6, response["totalCount"])
+}
+
+func TestRSSHandler_GetLatest_TotalCountFromCache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewRSSHandler()
+	handler.cache = &cacheEntry{
+		data:       &shared.RssHeadline{Title: "Cached", Link: "https://test.com", Source: "TEST"},
+		totalCount: 42,
+		timestamp:  time.Now(),
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/rss/spiegel/latest", nil)
+
+	handler.GetLatest(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response LatestHeadlineResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Cached", response.Title)
+	assert.Equal(t, 42, response.TotalCount)
 }
 
 func TestRSSHandler_GetLatest_NetworkError(t *testing.T) {
@@ -93,6 +118,7 @@ func TestRSSHandler_GetLatest_ServerError(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.Equal(t, "Unable to fetch RSS feed", response.Error)
+	assert.Equal(t, CodeUpstreamStatus, response.Code)
 }
 
 func TestRSSHandler_GetTop5_Success(t *testing.T) {
@@ -122,7 +148,7 @@ func TestRSSHandler_GetTop5_Success(t *testing.T) {
 	assert.Len(t, response.Headlines, 5)
 	assert.Equal(t, "Headline 1", response.Headlines[0].Title)
 	assert.Equal(t, "https://www.spiegel.de/1", response.Headlines[0].Link)
-	assert.Equal(t, "SPIEGEL", response.Headlines[0].Source)
+	assert.Equal(t, "SPIEGEL ONLINE", response.Headlines[0].Source)
 }
 
 func TestRSSHandler_GetTop5_WithLimit(t *testing.T) {
@@ -292,4 +318,29 @@ func TestRSSHandler_ResetCache(t *testing.T) {
 	// Verify cache is empty
 	assert.Nil(t, handler.cache.data)
 	assert.Empty(t, handler.multiCache.data)
-}
\ No newline at end of file
+}
+func TestRSSHandler_ChannelTitleAsSource(t *testing.T) {
+	handler := NewRSSHandler()
+
+	heiseFeed := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>Heise Online</title>
+    <item>
+      <title>Neue CPU vorgestellt</title>
+      <link>https://www.heise.de/1</link>
+      <pubDate>Mon, 24 Sep 2023 10:00:00 +0000</pubDate>
+    </item>
+  </channel>
+</rss>`
+
+	headlines := handler.parseMultipleRSSItems(heiseFeed, 10)
+	assert.Len(t, headlines, 1)
+	assert.Equal(t, "Heise Online", headlines[0].Source)
+
+	// A feed without a channel title falls back to the default source key.
+	untitledFeed := strings.Replace(heiseFeed, "<title>Heise Online</title>", "", 1)
+	headlines = handler.parseMultipleRSSItems(untitledFeed, 10)
+	assert.Len(t, headlines, 1)
+	assert.Equal(t, defaultSourceKey, headlines[0].Source)
+}
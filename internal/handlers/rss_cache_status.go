@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CacheStatusResponse describes the cached headlines of a source.
+type CacheStatusResponse struct {
+	Cached     bool `json:"cached" example:"true"`
+	AgeSeconds int  `json:"age_seconds" example:"42"`
+	Items      int  `json:"items" example:"250"`
+	TTLSeconds int  `json:"ttl_seconds" example:"300"`
+}
+
+// GetCacheStatus handles GET /api/rss/{source}/cache
+// @Summary      Show the headlines cache of a source
+// @Description  Reports whether headlines of the source are cached, how old they are and how many there are; an expired cache still counts as cached until it is refetched
+// @Tags         rss
+// @Produce      json
+// @Param        source   path      string  true  "Feed source key (see /rss/sources)"
+// @Success      200      {object}  CacheStatusResponse
+// @Failure      404      {object}  ErrorResponse
+// @Router       /rss/{source}/cache [get]
+func (h *RSSHandler) GetCacheStatus(c *gin.Context) {
+	source, ok := h.sources.find(c.Param("source"))
+	if !ok {
+		respondUnknownSource(c, c.Param("source"))
+		return
+	}
+
+	h.mu.RLock()
+	entry := h.multiCache
+	if source.URL != "" {
+		entry = h.sourceCache[source.Key]
+	}
+	status := CacheStatusResponse{TTLSeconds: int(cacheTTL / time.Second)}
+	if entry != nil && len(entry.data) > 0 {
+		status.Cached = true
+		status.AgeSeconds = int(time.Since(entry.timestamp) / time.Second)
+		status.Items = len(entry.data)
+	}
+	h.mu.RUnlock()
+
+	c.JSON(http.StatusOK, status)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/f00b455/golang-template/pkg/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func doCacheStatusRequest(t *testing.T, handler *RSSHandler, source string) (int, CacheStatusResponse) {
+	w := httptest.NewRecorder()
+	newSourcesRouter(handler).ServeHTTP(w, httptest.NewRequest("GET", "/api/rss/"+source+"/cache", nil))
+
+	var response CacheStatusResponse
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	}
+	return w.Code, response
+}
+
+func TestRSSHandler_GetCacheStatus_Populated(t *testing.T) {
+	handler := NewRSSHandler()
+	handler.multiCache = &multiCacheEntry{
+		data:      make([]shared.RssHeadline, 7),
+		timestamp: time.Now().Add(-3 * time.Second),
+	}
+
+	code, status := doCacheStatusRequest(t, handler, "spiegel")
+	require.Equal(t, http.StatusOK, code)
+	assert.True(t, status.Cached)
+	assert.Equal(t, 7, status.Items)
+	assert.GreaterOrEqual(t, status.AgeSeconds, 3)
+	assert.Less(t, status.AgeSeconds, 10)
+	assert.Equal(t, 300, status.TTLSeconds)
+}
+
+func TestRSSHandler_GetCacheStatus_Empty(t *testing.T) {
+	handler := NewRSSHandler()
+	handler.ResetCache()
+
+	code, status := doCacheStatusRequest(t, handler, "spiegel")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, CacheStatusResponse{Cached: false, TTLSeconds: 300}, status)
+}
+
+func TestRSSHandler_GetCacheStatus_RegisteredSource(t *testing.T) {
+	handler := NewRSSHandler()
+	handler.sources.register(feedSource{SourceInfo: SourceInfo{Key: "heise", Name: "heise online"}, URL: "https://www.heise.de/rss/heise.rdf"})
+
+	code, status := doCacheStatusRequest(t, handler, "heise")
+	require.Equal(t, http.StatusOK, code)
+	assert.False(t, status.Cached)
+
+	handler.sourceCache["heise"] = &multiCacheEntry{data: make([]shared.RssHeadline, 2), timestamp: time.Now()}
+	_, status = doCacheStatusRequest(t, handler, "heise")
+	assert.True(t, status.Cached)
+	assert.Equal(t, 2, status.Items)
+}
+
+func TestRSSHandler_GetCacheStatus_UnknownSource(t *testing.T) {
+	code, _ := doCacheStatusRequest(t, NewRSSHandler(), "unknown")
+	assert.Equal(t, http.StatusNotFound, code)
+}
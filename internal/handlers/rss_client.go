@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/f00b455/golang-template/internal/config"
+)
+
+// transportSettings are the connection pool settings of a shared client.
+type transportSettings struct {
+	maxIdleConns        int
+	maxIdleConnsPerHost int
+	idleConnTimeout     time.Duration
+	// publicOnly refuses connections to non-public addresses, see newPublicOnlyTransport
+	publicOnly bool
+}
+
+// sharedClients holds one HTTP client per pool configuration. Every RSSHandler
+// built with NewRSSHandler uses the client matching its configuration, so all
+// handlers draw from one pool of keep-alive connections instead of each
+// opening its own sockets.
+var (
+	sharedClientsMu sync.Mutex
+	sharedClients   = make(map[transportSettings]*http.Client)
+)
+
+// sharedHTTPClient returns the shared client for the pool settings in cfg. It
+// has no client-wide timeout: every fetch is bounded by RSS_REQUEST_TIMEOUT
+// through its request context.
+func sharedHTTPClient(cfg *config.Config) *http.Client {
+	return sharedClient(transportSettings{
+		maxIdleConns:        cfg.RSSMaxIdleConns,
+		maxIdleConnsPerHost: cfg.RSSMaxIdleConnsPerHost,
+		idleConnTimeout:     cfg.RSSIdleConnTimeout,
+	})
+}
+
+// sharedSourceClient returns the shared client imported sources are fetched
+// with. Unlike sharedHTTPClient it only connects to public addresses.
+func sharedSourceClient(cfg *config.Config) *http.Client {
+	return sharedClient(transportSettings{
+		maxIdleConns:        cfg.RSSMaxIdleConns,
+		maxIdleConnsPerHost: cfg.RSSMaxIdleConnsPerHost,
+		idleConnTimeout:     cfg.RSSIdleConnTimeout,
+		publicOnly:          true,
+	})
+}
+
+// sharedClient returns the client for settings, creating it on first use.
+func sharedClient(settings transportSettings) *http.Client {
+	sharedClientsMu.Lock()
+	defer sharedClientsMu.Unlock()
+
+	client, ok := sharedClients[settings]
+	if !ok {
+		transport := newPooledTransport(settings)
+		if settings.publicOnly {
+			transport = newPublicOnlyTransport(settings, isPublicAddrPort)
+		}
+		client = &http.Client{Transport: transport}
+		sharedClients[settings] = client
+	}
+	return client
+}
+
+// newPooledTransport returns a keep-alive transport that keeps idle connections
+// to the feed hosts open for reuse as configured by settings.
+func newPooledTransport(settings transportSettings) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   5 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	return &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		DialContext:         dialer.DialContext,
+		MaxIdleConns:        settings.maxIdleConns,
+		MaxIdleConnsPerHost: settings.maxIdleConnsPerHost,
+		IdleConnTimeout:     settings.idleConnTimeout,
+		TLSHandshakeTimeout: 5 * time.Second,
+	}
+}
+
+// newPublicOnlyTransport returns a pooled transport that refuses to connect to
+// addresses allow rejects. The check runs on the address actually dialed, so it
+// also covers redirects and hosts that resolve differently than at import. It
+// connects directly, as a proxy would dial the feed host on its behalf.
+func newPublicOnlyTransport(settings transportSettings, allow func(netip.AddrPort) bool) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   5 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addr, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrNonPublicSource, err)
+			}
+			if !allow(addr) {
+				return fmt.Errorf("%w: refusing to connect to %s", ErrNonPublicSource, addr.Addr())
+			}
+			return nil
+		},
+	}
+	transport := newPooledTransport(settings)
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
+
+// isPublicAddrPort reports whether addr is a public address, see isPublicIP.
+func isPublicAddrPort(addr netip.AddrPort) bool {
+	return isPublicIP(net.IP(addr.Addr().Unmap().AsSlice()))
+}
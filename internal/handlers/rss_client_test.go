@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/f00b455/golang-template/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingClient returns a client on the pooled transport that counts new connections.
+func countingClient(dials *int32) *http.Client {
+	transport := sharedHTTPClient(config.Load()).Transport.(*http.Transport).Clone()
+	dial := transport.DialContext
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		atomic.AddInt32(dials, 1)
+		return dial(ctx, network, addr)
+	}
+	return &http.Client{Transport: transport}
+}
+
+func TestNewRSSHandler_SharesHTTPClient(t *testing.T) {
+	assert.Same(t, NewRSSHandler().httpClient, NewRSSHandler().httpClient)
+}
+
+func TestNewRSSHandler_UsesTunedTransport(t *testing.T) {
+	transport, ok := NewRSSHandler().httpClient.Transport.(*http.Transport)
+	require.True(t, ok, "the shared client must not fall back to http.DefaultTransport")
+
+	assert.False(t, transport.DisableKeepAlives)
+	assert.Equal(t, 100, transport.MaxIdleConns)
+	assert.Equal(t, 10, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 90*time.Second, transport.IdleConnTimeout)
+}
+
+func TestNewRSSHandler_TransportFromConfig(t *testing.T) {
+	t.Setenv("RSS_MAX_IDLE_CONNS", "20")
+	t.Setenv("RSS_MAX_IDLE_CONNS_PER_HOST", "4")
+	t.Setenv("RSS_IDLE_CONN_TIMEOUT", "15s")
+
+	handler := NewRSSHandler()
+	transport := handler.httpClient.Transport.(*http.Transport)
+	assert.Equal(t, 20, transport.MaxIdleConns)
+	assert.Equal(t, 4, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 15*time.Second, transport.IdleConnTimeout)
+	assert.False(t, transport.DisableKeepAlives)
+
+	// Handlers with the same tuning still share one pool.
+	assert.Same(t, handler.httpClient, NewRSSHandler().httpClient)
+}
+
+func TestNewRSSHandler_ImportedSourcesUsePublicOnlyClient(t *testing.T) {
+	handler := NewRSSHandler()
+	assert.NotSame(t, handler.httpClient, handler.sourceClient)
+	assert.Same(t, handler.sourceClient, NewRSSHandler().sourceClient)
+
+	server := SetupMockServer(MockRSSResponse, http.StatusOK)
+	defer server.Close()
+	_, err := handler.sourceClient.Get(server.URL)
+	assert.ErrorIs(t, err, ErrNonPublicSource, "loopback is refused when dialing, whatever the host resolved to at import")
+}
+
+func TestPublicOnlyTransport_RefusesRedirectToLoopback(t *testing.T) {
+	internal := SetupMockServer(MockRSSResponse, http.StatusOK)
+	defer internal.Close()
+	redirecting := httptest.NewServer(http.RedirectHandler(internal.URL, http.StatusFound))
+	defer redirecting.Close()
+
+	// The redirecting server stands in for a public feed host
+	publicAddr := netip.MustParseAddrPort(redirecting.Listener.Addr().String())
+	transport := newPublicOnlyTransport(transportSettings{maxIdleConns: 1, maxIdleConnsPerHost: 1}, func(addr netip.AddrPort) bool {
+		return addr == publicAddr || isPublicAddrPort(addr)
+	})
+
+	handler := NewRSSHandlerWithClient(&http.Client{Transport: transport})
+	handler.cfg.RSSRetryCount = 0
+	source := feedSource{SourceInfo: SourceInfo{Key: "redirecting", Name: "Redirecting"}, URL: redirecting.URL}
+	handler.sources.register(source)
+
+	_, err := handler.headlinesForSource(context.Background(), source)
+	assert.ErrorIs(t, err, ErrNonPublicSource)
+}
+
+func TestPooledTransport_ReusesConnections(t *testing.T) {
+	server := SetupMockServer(MockRSSResponse, http.StatusOK)
+	defer server.Close()
+
+	var dials int32
+	handler := NewRSSHandlerWithClient(countingClient(&dials))
+	handler.cfg.SpiegelRSSURL = server.URL
+
+	for i := 0; i < 5; i++ {
+		_, err := handler.fetchRSSFeed(context.Background())
+		require.NoError(t, err)
+	}
+
+	assert.EqualValues(t, 1, atomic.LoadInt32(&dials), "sequential fetches reuse one keep-alive connection")
+}
+
+func BenchmarkFetchRSSFeed_PooledTransport(b *testing.B) {
+	server := SetupMockServer(MockRSSResponse, http.StatusOK)
+	defer server.Close()
+
+	var dials int32
+	handler := NewRSSHandlerWithClient(countingClient(&dials))
+	handler.cfg.SpiegelRSSURL = server.URL
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := handler.fetchRSSFeed(context.Background()); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(atomic.LoadInt32(&dials)), "dials")
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/f00b455/golang-template/pkg/shared"
+	"github.com/gin-gonic/gin"
+)
+
+// dateRange bounds headlines by publication time. A zero bound is open-ended,
+// so a range with only from means "since" and one with only to means "until".
+type dateRange struct {
+	from time.Time
+	to   time.Time
+}
+
+// parseDateRange reads the optional from and to query parameters as RFC3339 timestamps.
+func parseDateRange(c *gin.Context) (dateRange, error) {
+	var r dateRange
+	var err error
+
+	if r.from, err = parseDateParam("from", c.Query("from")); err != nil {
+		return dateRange{}, err
+	}
+	if r.to, err = parseDateParam("to", c.Query("to")); err != nil {
+		return dateRange{}, err
+	}
+	if !r.from.IsZero() && !r.to.IsZero() && r.from.After(r.to) {
+		return dateRange{}, fmt.Errorf("invalid date range: from must not be after to")
+	}
+	return r, nil
+}
+
+// parseDateParam parses a single RFC3339 query value; an empty value yields the zero time.
+func parseDateParam(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s parameter: must be an RFC3339 date (e.g. 2023-09-24T08:00:00Z)", name)
+	}
+	return t, nil
+}
+
+// isZero reports whether the range leaves both ends open.
+func (r dateRange) isZero() bool {
+	return r.from.IsZero() && r.to.IsZero()
+}
+
+// contains reports whether t lies within the range, inclusive of both bounds.
+func (r dateRange) contains(t time.Time) bool {
+	if !r.from.IsZero() && t.Before(r.from) {
+		return false
+	}
+	if !r.to.IsZero() && t.After(r.to) {
+		return false
+	}
+	return true
+}
+
+// filterByDateRange keeps headlines whose PublishedAt falls within the range.
+// Headlines with an unparsable date are dropped once a bound is set.
+func (h *RSSHandler) filterByDateRange(headlines []shared.RssHeadline, r dateRange) []shared.RssHeadline {
+	if r.isZero() {
+		return headlines
+	}
+
+	filtered := make([]shared.RssHeadline, 0, len(headlines))
+	for _, headline := range headlines {
+		publishedAt, err := time.Parse(time.RFC3339, headline.PublishedAt)
+		if err == nil && r.contains(publishedAt) {
+			filtered = append(filtered, headline)
+		}
+	}
+	return filtered
+}
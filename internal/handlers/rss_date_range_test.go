@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// doTop5Request calls GetTop5 with the given query parameters.
+func doTop5Request(handler *RSSHandler, query url.Values) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/rss/spiegel/top5?"+query.Encode(), nil)
+
+	handler.GetTop5(c)
+	return w
+}
+
+func TestRSSHandler_GetTop5_DateRangeInclusive(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+
+	w := doTop5Request(handler, url.Values{
+		"limit": {"10"},
+		"from":  {"2023-09-24T07:00:00Z"},
+		"to":    {"2023-09-24T09:00:00Z"},
+	})
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response HeadlinesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Headlines, 3)
+	assert.Equal(t, "2023-09-24T09:00:00Z", response.Headlines[0].PublishedAt)
+	assert.Equal(t, "2023-09-24T07:00:00Z", response.Headlines[2].PublishedAt)
+}
+
+func TestRSSHandler_ExportHeadlines_DateRangeSinceOnly(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+
+	w := doExportRequest(handler, url.Values{
+		"format": {"json"},
+		"from":   {"2023-09-24T09:00:00Z"},
+	}.Encode())
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		TotalItems int `json:"total_items"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.TotalItems)
+}
+
+func TestRSSHandler_DateRangeInvalid(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+
+	tests := []struct {
+		name          string
+		query         url.Values
+		expectedError string
+	}{
+		{
+			name:          "invalid from",
+			query:         url.Values{"from": {"24.09.2023"}},
+			expectedError: "invalid from parameter",
+		},
+		{
+			name:          "invalid to",
+			query:         url.Values{"to": {"yesterday"}},
+			expectedError: "invalid to parameter",
+		},
+		{
+			name:          "from after to",
+			query:         url.Values{"from": {"2023-09-25T00:00:00Z"}, "to": {"2023-09-24T00:00:00Z"}},
+			expectedError: "invalid date range",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			top5 := doTop5Request(handler, tt.query)
+
+			exportQuery := url.Values{"format": {"csv"}}
+			for key, values := range tt.query {
+				exportQuery[key] = values
+			}
+			export := doExportRequest(handler, exportQuery.Encode())
+
+			for _, w := range []*httptest.ResponseRecorder{top5, export} {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				var response ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Contains(t, response.Error, tt.expectedError)
+			}
+		})
+	}
+}
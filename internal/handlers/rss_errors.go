@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Error codes reported in ErrorResponse.Code so clients can react to specific failures.
+const (
+	CodeBadRequest          = "BAD_REQUEST"
+	CodeUpstreamTimeout     = "UPSTREAM_TIMEOUT"
+	CodeUpstreamStatus      = "UPSTREAM_STATUS"
+	CodeUpstreamUnavailable = "UPSTREAM_UNAVAILABLE"
+	CodeParseError          = "PARSE_ERROR"
+	CodeCircuitOpen         = "CIRCUIT_OPEN"
+	CodePayloadTooLarge     = "PAYLOAD_TOO_LARGE"
+)
+
+var (
+	// ErrUpstreamTimeout is returned when the feed does not answer within RSS_REQUEST_TIMEOUT.
+	ErrUpstreamTimeout = errors.New("upstream request timed out")
+	// ErrUpstreamStatus is returned when the feed answers with a non-200 status.
+	ErrUpstreamStatus = errors.New("upstream returned an unexpected status")
+	// ErrPayloadTooLarge is returned when the feed body exceeds the configured size limit.
+	ErrPayloadTooLarge = errors.New("upstream response too large")
+	// ErrParse is returned when the feed contains no parseable items.
+	ErrParse = errors.New("no RSS items could be parsed")
+)
+
+// upstreamErrorCode maps a fetch error to its ErrorResponse code.
+func upstreamErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrUpstreamTimeout):
+		return CodeUpstreamTimeout
+	case errors.Is(err, ErrUpstreamStatus):
+		return CodeUpstreamStatus
+	case errors.Is(err, ErrParse):
+		return CodeParseError
+	case errors.Is(err, ErrCircuitOpen):
+		return CodeCircuitOpen
+	case errors.Is(err, ErrPayloadTooLarge):
+		return CodePayloadTooLarge
+	default:
+		return CodeUpstreamUnavailable
+	}
+}
+
+// respondUpstreamError writes the error response for a failed feed fetch:
+// 504 when the feed timed out, 503 for every other upstream failure.
+func respondUpstreamError(c *gin.Context, err error) {
+	status := http.StatusServiceUnavailable
+	if errors.Is(err, ErrUpstreamTimeout) {
+		status = http.StatusGatewayTimeout
+	}
+	c.JSON(status, ErrorResponse{
+		Error: "Unable to fetch RSS feed",
+		Code:  upstreamErrorCode(err),
+	})
+}
+
+// staleWarning is the Warning header (RFC 7234, section 5.5.1) sent with
+// headlines served from an expired cache because the feed could not be fetched.
+const staleWarning = `110 - "Response is Stale"`
+
+// respondStale marks the response as served from an expired cache after the
+// feed fetch failed with err.
+func respondStale(c *gin.Context, err error) {
+	log.Printf("Serving stale headlines, fetching the feed failed: %v", err)
+	c.Header("Warning", staleWarning)
+}
+
+// respondBadRequest writes a 400 response carrying the validation error message.
+func respondBadRequest(c *gin.Context, err error) {
+	c.JSON(http.StatusBadRequest, ErrorResponse{
+		Error: err.Error(),
+		Code:  CodeBadRequest,
+	})
+}
+
+// isTimeout reports whether err is a network timeout, such as http.Client.Timeout expiring.
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/f00b455/golang-template/internal/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowTransport blocks until the request is cancelled, simulating an upstream that never answers.
+func slowTransport() http.RoundTripper {
+	return &testutil.MockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			select {
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			case <-time.After(5 * time.Second):
+				return nil, errors.New("slow transport was not cancelled")
+			}
+		},
+	}
+}
+
+func TestFetchRSSFeed_ConfiguredRequestTimeout(t *testing.T) {
+	handler := NewRSSHandlerWithClient(&http.Client{Transport: slowTransport()})
+	handler.cfg.SpiegelRSSURL = "http://feed.test/rss"
+	handler.cfg.RSSRequestTimeout = 20 * time.Millisecond
+
+	start := time.Now()
+	_, err := handler.fetchRSSFeed(context.Background())
+
+	assert.ErrorIs(t, err, ErrUpstreamTimeout)
+	assert.ErrorContains(t, err, "after 20ms")
+	assert.Less(t, time.Since(start), time.Second, "the configured timeout should cut the fetch short")
+}
+
+func TestFetchRSSFeed_ParentDeadlineIsNotUpstreamTimeout(t *testing.T) {
+	handler := NewRSSHandlerWithClient(&http.Client{Transport: slowTransport()})
+	handler.cfg.SpiegelRSSURL = "http://feed.test/rss"
+	handler.cfg.RSSRequestTimeout = 5 * time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := handler.fetchRSSFeed(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NotErrorIs(t, err, ErrUpstreamTimeout, "the caller's deadline is not the feed's fault")
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRSSHandler_ErrorCodes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const emptyFeed = `<?xml version="1.0"?><rss version="2.0"><channel><title>Empty</title></channel></rss>`
+
+	tests := []struct {
+		name           string
+		client         func(t *testing.T) *http.Client
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "upstream timeout",
+			client: func(t *testing.T) *http.Client {
+				return &http.Client{Timeout: 50 * time.Millisecond, Transport: slowTransport()}
+			},
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedCode:   CodeUpstreamTimeout,
+		},
+		{
+			name: "upstream 500",
+			client: func(t *testing.T) *http.Client {
+				return &http.Client{Transport: &testutil.MockTransport{
+					RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+						return &http.Response{
+							StatusCode: http.StatusInternalServerError,
+							Body:       testutil.CreateReadCloser(""),
+							Header:     make(http.Header),
+							Request:    req,
+						}, nil
+					},
+				}}
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCode:   CodeUpstreamStatus,
+		},
+		{
+			name: "connection failure",
+			client: func(t *testing.T) *http.Client {
+				return &http.Client{Transport: &testutil.MockTransport{
+					RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+						return nil, errors.New("connection refused")
+					},
+				}}
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCode:   CodeUpstreamUnavailable,
+		},
+		{
+			name: "feed without items",
+			client: func(t *testing.T) *http.Client {
+				return testutil.CreateMockHTTPClient(t, emptyFeed)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCode:   CodeParseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewRSSHandlerWithClient(tt.client(t))
+			handler.cfg.SpiegelRSSURL = "http://feed.test/rss"
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/rss/spiegel/top5", nil)
+			handler.GetTop5(c)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			var response ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "Unable to fetch RSS feed", response.Error)
+			assert.Equal(t, tt.expectedCode, response.Code)
+		})
+	}
+}
+
+func TestRSSHandler_BadRequestCode(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+
+	w := doExportRequest(handler, "format=pdf")
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, CodeBadRequest, response.Code)
+}
+
+func TestRSSHandler_UpstreamTimeoutReturns504(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewRSSHandlerWithClient(&http.Client{Timeout: 50 * time.Millisecond, Transport: slowTransport()})
+	handler.cfg.SpiegelRSSURL = "http://feed.test/rss"
+
+	endpoints := map[string]gin.HandlerFunc{
+		"/rss/spiegel/latest":             handler.GetLatest,
+		"/rss/spiegel/top5":               handler.GetTop5,
+		"/rss/spiegel/export?format=json": handler.ExportHeadlines,
+	}
+	for target, endpoint := range endpoints {
+		t.Run(target, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", target, nil)
+			endpoint(c)
+
+			assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+		})
+	}
+}
+
+func TestRSSHandler_ClientCancellationAbortsFetch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewRSSHandlerWithClient(&http.Client{Transport: slowTransport()})
+	handler.cfg.SpiegelRSSURL = "http://feed.test/rss"
+	handler.cfg.RSSRequestTimeout = 5 * time.Second
+
+	endpoints := map[string]gin.HandlerFunc{
+		"/rss/spiegel/latest":             handler.GetLatest,
+		"/rss/spiegel/top5":               handler.GetTop5,
+		"/rss/spiegel/export?format=json": handler.ExportHeadlines,
+	}
+	for target, endpoint := range endpoints {
+		t.Run(target, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(20*time.Millisecond, cancel)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", target, nil).WithContext(ctx)
+
+			start := time.Now()
+			endpoint(c)
+
+			assert.Less(t, time.Since(start), time.Second, "a cancelled request should not wait for the upstream")
+		})
+	}
+
+	handler.breaker.mu.Lock()
+	defer handler.breaker.mu.Unlock()
+	assert.Zero(t, handler.breaker.failures, "cancelled fetches must not count as feed failures")
+}
+
+func TestFetchRSSFeed_CancelledContextReturnsCancellation(t *testing.T) {
+	handler := NewRSSHandlerWithClient(&http.Client{Transport: slowTransport()})
+	handler.cfg.SpiegelRSSURL = "http://feed.test/rss"
+	handler.cfg.RSSRequestTimeout = 5 * time.Second
+
+	fetches := map[string]func(ctx context.Context) error{
+		"fetchLatestHeadline": func(ctx context.Context) error {
+			_, _, err := handler.fetchLatestHeadline(ctx)
+			return err
+		},
+		"fetchMultipleHeadlines": func(ctx context.Context) error {
+			_, err := handler.fetchMultipleHeadlines(ctx, handler.cfg.RSSMaxFetchItems)
+			return err
+		},
+	}
+	for name, fetch := range fetches {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(20*time.Millisecond, cancel)
+
+			start := time.Now()
+			err := fetch(ctx)
+
+			assert.ErrorIs(t, err, context.Canceled)
+			assert.NotErrorIs(t, err, ErrUpstreamTimeout)
+			assert.Less(t, time.Since(start), time.Second)
+		})
+	}
+}
+
+func TestRSSHandler_ServesStaleCacheWhenFeedFails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var failing atomic.Bool
+	client := &http.Client{Transport: &testutil.MockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			if failing.Load() {
+				return statusResponse(req, http.StatusBadGateway, ""), nil
+			}
+			return statusResponse(req, http.StatusOK, MockRSSResponse), nil
+		},
+	}}
+	handler := newRetryTestHandler(client)
+
+	endpoints := map[string]gin.HandlerFunc{
+		"/rss/spiegel/latest":        handler.GetLatest,
+		"/rss/spiegel/top5?limit=10": handler.GetTop5,
+	}
+	serve := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", target, nil)
+		endpoints[target](c)
+		return w
+	}
+	for target := range endpoints {
+		require.Equal(t, http.StatusOK, serve(target).Code)
+	}
+
+	// The feed starts failing once both caches have expired
+	failing.Store(true)
+	handler.mu.Lock()
+	handler.cache.timestamp = time.Now().Add(-2 * cacheTTL)
+	handler.multiCache.timestamp = time.Now().Add(-2 * cacheTTL)
+	handler.mu.Unlock()
+	handler.responses.purge()
+
+	t.Run("latest", func(t *testing.T) {
+		w := serve("/rss/spiegel/latest")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, staleWarning, w.Header().Get("Warning"))
+		var response LatestHeadlineResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Headline 1", response.Title)
+		assert.Equal(t, 6, response.TotalCount)
+	})
+
+	t.Run("top5", func(t *testing.T) {
+		w := serve("/rss/spiegel/top5?limit=10")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, staleWarning, w.Header().Get("Warning"))
+		var response HeadlinesResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Headlines, 6)
+		assert.Equal(t, 6, response.TotalCount)
+	})
+
+	t.Run("nothing cached", func(t *testing.T) {
+		handler.ResetCache()
+
+		w := serve("/rss/spiegel/top5?limit=10")
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Empty(t, w.Header().Get("Warning"))
+	})
+}
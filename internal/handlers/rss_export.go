@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/f00b455/golang-template/pkg/shared"
+	"github.com/gin-gonic/gin"
+)
+
+// supportedExportFormats lists the accepted values of the format query parameter.
+var supportedExportFormats = []string{"json", "csv", "tsv", "rss", "md", "jsonfeed"}
+
+// exportRetryAfterSeconds is the Retry-After hint sent when all export slots are busy.
+const exportRetryAfterSeconds = 5
+
+// exportFileExtensions maps each export format to the file extension used in the download filename.
+var exportFileExtensions = map[string]string{
+	"json":     "json",
+	"csv":      "csv",
+	"tsv":      "tsv",
+	"rss":      "xml",
+	"md":       "md",
+	"jsonfeed": "json",
+}
+
+// validateExportFormat checks if the export format is valid
+func (h *RSSHandler) validateExportFormat(format string) error {
+	if format == "" {
+		return fmt.Errorf("missing format parameter")
+	}
+	if _, ok := exportFileExtensions[format]; !ok {
+		return fmt.Errorf("invalid format parameter: must be one of %s", strings.Join(supportedExportFormats, ", "))
+	}
+	return nil
+}
+
+// prepareExportData fetches and filters headlines for export
+func (h *RSSHandler) prepareExportData(ctx context.Context, params *exportParams) ([]shared.RssHeadline, error) {
+	headlines, _ := h.getCachedHeadlines()
+	if headlines == nil {
+		var err error
+		headlines, err = h.fetchAndCacheHeadlines(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	headlines = h.filterByDateRange(headlines, params.dateRange)
+
+	// Apply filter
+	if params.filter != "" {
+		headlines = h.filterHeadlines(headlines, params.filter)
+	}
+
+	// Apply limit
+	if params.limit > 0 && len(headlines) > params.limit {
+		headlines = headlines[:params.limit]
+	}
+
+	return headlines, nil
+}
+
+// generateExportFilename creates a filename for export with optional filter
+func (h *RSSHandler) generateExportFilename(format, filter string) string {
+	timestamp := time.Now().Format("20060102_150405")
+	extension := exportFileExtensions[format]
+	if filter != "" {
+		return fmt.Sprintf("rss_export_%s_%s.%s", filter, timestamp, extension)
+	}
+	return fmt.Sprintf("rss_export_%s.%s", timestamp, extension)
+}
+
+// ExportHeadlines handles GET /api/rss/spiegel/export
+// @Summary      Export SPIEGEL RSS headlines
+// @Description  Exports RSS headlines in JSON, CSV, TSV, RSS, Markdown or JSON Feed format
+// @Tags         rss
+// @Accept       json
+// @Produce      json
+// @Produce      text/csv
+// @Produce      text/tab-separated-values
+// @Produce      application/rss+xml
+// @Produce      text/markdown
+// @Produce      application/feed+json
+// @Param        format   query     string  true   "Export format (json, csv, tsv, rss, md or jsonfeed)"
+// @Param        filter   query     string  false  "Filter headlines by keyword"
+// @Param        limit    query     int     false  "Number of headlines to export (1-1000)" minimum(1) maximum(1000)
+// @Param        columns  query     string  false  "Comma-separated extended CSV columns to append (published_date)"
+// @Param        fields   query     string  false  "Comma-separated fields to include in CSV/JSON (title,link,published_at,source)"
+// @Param        stream   query     bool    false  "Stream CSV with chunked transfer encoding instead of buffering"
+// @Param        delimiter query   string  false  "CSV delimiter (comma, semicolon or tab)" default(comma)
+// @Param        bom      query     bool    false  "Prepend a UTF-8 BOM to CSV output for Excel"
+// @Param        header   query     bool    false  "Include the CSV header row" default(true)
+// @Param        from     query     string  false  "Only headlines published at or after this RFC3339 date"
+// @Param        to       query     string  false  "Only headlines published at or before this RFC3339 date"
+// @Param        strict   query     bool    false  "Reject repeated query parameters instead of using the first value"
+// @Success      200      {object}  object
+// @Failure      400      {object}  ErrorResponse
+// @Failure      503      {object}  ErrorResponse
+// @Failure      504      {object}  ErrorResponse
+// @Router       /rss/spiegel/export [get]
+func (h *RSSHandler) ExportHeadlines(c *gin.Context) {
+	params, err := h.validateExportParams(c)
+	if err != nil {
+		respondBadRequest(c, err)
+		return
+	}
+
+	if !h.acquireExportSlot() {
+		c.Header("Retry-After", strconv.Itoa(exportRetryAfterSeconds))
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{Error: "Too many concurrent exports, please retry later"})
+		return
+	}
+	defer h.releaseExportSlot()
+
+	headlines, err := h.prepareExportData(c.Request.Context(), params)
+	if err != nil {
+		respondUpstreamError(c, err)
+		return
+	}
+
+	h.performExport(c, headlines, params)
+}
+
+// acquireExportSlot reserves an export slot without blocking; it reports false when all slots are in use.
+func (h *RSSHandler) acquireExportSlot() bool {
+	select {
+	case h.exportSlots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// releaseExportSlot frees a slot reserved by acquireExportSlot.
+func (h *RSSHandler) releaseExportSlot() {
+	<-h.exportSlots
+}
+
+// exportParams holds validated export parameters
+type exportParams struct {
+	format  string
+	filter  string
+	limit   int
+	columns []csvColumn
+	// projected is set when the fields parameter restricts the exported columns
+	projected bool
+	stream    bool
+	delimiter rune
+	bom       bool
+	// header is false when the CSV/TSV header row should be omitted
+	header    bool
+	dateRange dateRange
+}
+
+// validateExportParams validates all export parameters
+func (h *RSSHandler) validateExportParams(c *gin.Context) (*exportParams, error) {
+	if err := checkDuplicateParams(c); err != nil {
+		return nil, err
+	}
+
+	format := c.Query("format")
+	if err := h.validateExportFormat(format); err != nil {
+		return nil, err
+	}
+
+	filter := c.Query("filter")
+	if err := h.validateFilter(filter); err != nil {
+		return nil, err
+	}
+
+	limit, err := h.validateAndParseExportLimit(c)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := resolveCSVColumns(c.Query("columns"))
+	if err != nil {
+		return nil, err
+	}
+
+	fields := c.Query("fields")
+	if columns, err = selectFields(columns, fields); err != nil {
+		return nil, err
+	}
+
+	delimiter, err := parseCSVDelimiter(c.Query("delimiter"))
+	if err != nil {
+		return nil, err
+	}
+	if format == "tsv" {
+		delimiter = '\t'
+	}
+
+	publishedRange, err := parseDateRange(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &exportParams{
+		format:    format,
+		filter:    filter,
+		limit:     limit,
+		columns:   columns,
+		projected: fields != "",
+		stream:    c.Query("stream") == "true",
+		delimiter: delimiter,
+		bom:       c.Query("bom") == "true",
+		header:    c.Query("header") != "false",
+		dateRange: publishedRange,
+	}, nil
+}
+
+// validateAndParseExportLimit validates and parses the export limit
+func (h *RSSHandler) validateAndParseExportLimit(c *gin.Context) (int, error) {
+	limitStr := c.Query("limit")
+	if limitStr == "" {
+		return maxExportItems, nil
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 1 {
+		return maxExportItems, nil
+	}
+
+	if limit > maxExportItems {
+		return 0, fmt.Errorf("limit exceeds maximum allowed value of %d", maxExportItems)
+	}
+
+	return limit, nil
+}
+
+// performExport executes the actual export based on format
+func (h *RSSHandler) performExport(c *gin.Context, headlines []shared.RssHeadline, params *exportParams) {
+	filename := h.generateExportFilename(params.format, params.filter)
+
+	// Let scripts see the size of the export before reading the body
+	c.Header("X-Total-Items", strconv.Itoa(len(headlines)))
+	c.Header("X-Export-Format", params.format)
+
+	switch params.format {
+	case "json":
+		h.exportAsJSON(c, headlines, params, filename)
+	case "rss":
+		h.exportAsRSS(c, headlines, filename)
+	case "md":
+		h.exportAsMarkdown(c, headlines, params.filter, filename)
+	case "jsonfeed":
+		h.exportAsJSONFeed(c, headlines, filename)
+	default:
+		h.exportAsCSV(c, headlines, params, filename)
+	}
+}
+
+func (h *RSSHandler) exportAsJSON(c *gin.Context, headlines []shared.RssHeadline, params *exportParams, filename string) {
+	response := struct {
+		ExportDate    string      `json:"export_date"`
+		TotalItems    int         `json:"total_items"`
+		FilterApplied string      `json:"filter_applied,omitempty"`
+		Headlines     interface{} `json:"headlines"`
+	}{
+		ExportDate: time.Now().Format(time.RFC3339),
+		TotalItems: len(headlines),
+		Headlines:  headlines,
+	}
+
+	if params.projected {
+		response.Headlines = projectHeadlines(headlines, params.columns)
+	}
+
+	if params.filter != "" {
+		response.FilterApplied = params.filter
+	}
+
+	// Marshal up front so Content-Length can be set
+	body, err := json.Marshal(response)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to generate JSON",
+		})
+		return
+	}
+
+	// Set security headers
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	c.Header("Content-Length", strconv.Itoa(len(body)))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("Content-Security-Policy", "default-src 'none'")
+	c.Data(http.StatusOK, "application/json", body)
+}
@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/f00b455/golang-template/pkg/shared"
+	"github.com/gin-gonic/gin"
+)
+
+// csvSchemaVersion identifies the documented CSV column layout. Bump it whenever
+// the default column set or order changes so downstream parsers can detect drift.
+const csvSchemaVersion = "1"
+
+// csvDelimiters maps the accepted delimiter query values to the csv.Writer separator.
+var csvDelimiters = map[string]rune{
+	"comma":     ',',
+	"semicolon": ';',
+	"tab":       '\t',
+}
+
+// delimitedContentTypes maps the delimited export formats to their Content-Type.
+var delimitedContentTypes = map[string]string{
+	"csv": "text/csv; charset=utf-8",
+	"tsv": "text/tab-separated-values; charset=utf-8",
+}
+
+// utf8BOM lets Excel on Windows detect UTF-8 so umlauts don't turn into mojibake.
+const utf8BOM = "\xEF\xBB\xBF"
+
+// csvStreamFlushRows is how many rows are written between flushes when streaming.
+const csvStreamFlushRows = 100
+
+// csvColumn describes a single exported column. key is the name used in the
+// columns and fields query parameters, jsonKey the name used in JSON projections.
+type csvColumn struct {
+	key     string
+	header  string
+	jsonKey string
+	value   func(shared.RssHeadline) string
+}
+
+// baseCSVColumns is the stable default layout: Title,Link,Published_At,Source.
+// Never reorder or extend it; add new fields to extendedCSVColumns instead.
+var baseCSVColumns = []csvColumn{
+	{key: "title", header: "Title", jsonKey: "title", value: func(h shared.RssHeadline) string { return h.Title }},
+	{key: "link", header: "Link", jsonKey: "link", value: func(h shared.RssHeadline) string { return h.Link }},
+	{key: "published_at", header: "Published_At", jsonKey: "publishedAt", value: func(h shared.RssHeadline) string { return h.PublishedAt }},
+	{key: "source", header: "Source", jsonKey: "source", value: func(h shared.RssHeadline) string { return h.Source }},
+}
+
+// extendedCSVColumns are opt-in columns appended after the base columns, in this
+// documented order, when requested via the columns query parameter.
+var extendedCSVColumns = []csvColumn{
+	{key: "published_date", header: "Published_Date", jsonKey: "publishedDate", value: publishedDateColumn},
+	{key: "categories", header: "Categories", jsonKey: "categories", value: func(h shared.RssHeadline) string { return strings.Join(h.Categories, ";") }},
+	{key: "image_url", header: "Image_URL", jsonKey: "imageUrl", value: func(h shared.RssHeadline) string { return h.ImageURL }},
+}
+
+// parseCSVDelimiter resolves the delimiter query value, defaulting to comma.
+func parseCSVDelimiter(value string) (rune, error) {
+	if value == "" {
+		return csvDelimiters["comma"], nil
+	}
+	delimiter, ok := csvDelimiters[strings.ToLower(value)]
+	if !ok {
+		return 0, fmt.Errorf("invalid delimiter parameter: must be comma, semicolon or tab")
+	}
+	return delimiter, nil
+}
+
+// parseColumnKeys splits a comma-separated column list into a set of normalized keys.
+func parseColumnKeys(requested string) map[string]bool {
+	keys := make(map[string]bool)
+	for _, key := range strings.Split(requested, ",") {
+		if key = strings.TrimSpace(strings.ToLower(key)); key != "" {
+			keys[key] = true
+		}
+	}
+	return keys
+}
+
+// resolveCSVColumns returns the base columns followed by the requested extended
+// columns in schema order, regardless of the order they were requested in.
+func resolveCSVColumns(requested string) ([]csvColumn, error) {
+	wanted := parseColumnKeys(requested)
+
+	columns := append([]csvColumn{}, baseCSVColumns...)
+	for _, column := range extendedCSVColumns {
+		if wanted[column.key] {
+			columns = append(columns, column)
+			delete(wanted, column.key)
+		}
+	}
+
+	if len(wanted) > 0 {
+		return nil, fmt.Errorf("invalid columns parameter: valid extended columns are %s", extendedCSVColumnKeys())
+	}
+	return columns, nil
+}
+
+// selectFields restricts columns to the requested fields, keeping schema order.
+// An empty fields value keeps every column.
+func selectFields(columns []csvColumn, fields string) ([]csvColumn, error) {
+	wanted := parseColumnKeys(fields)
+	if len(wanted) == 0 {
+		return columns, nil
+	}
+
+	selected := make([]csvColumn, 0, len(wanted))
+	for _, column := range columns {
+		if wanted[column.key] {
+			selected = append(selected, column)
+			delete(wanted, column.key)
+		}
+	}
+
+	if len(wanted) > 0 {
+		return nil, fmt.Errorf("invalid fields parameter: valid fields are %s", columnKeys(columns))
+	}
+	return selected, nil
+}
+
+// projectHeadlines reduces each headline to the selected columns, keyed by their JSON names.
+func projectHeadlines(headlines []shared.RssHeadline, columns []csvColumn) []map[string]string {
+	projected := make([]map[string]string, len(headlines))
+	for i, headline := range headlines {
+		item := make(map[string]string, len(columns))
+		for _, column := range columns {
+			item[column.jsonKey] = column.value(headline)
+		}
+		projected[i] = item
+	}
+	return projected
+}
+
+func extendedCSVColumnKeys() string {
+	return columnKeys(extendedCSVColumns)
+}
+
+func columnKeys(columns []csvColumn) string {
+	keys := make([]string, len(columns))
+	for i, column := range columns {
+		keys[i] = column.key
+	}
+	return strings.Join(keys, ",")
+}
+
+func csvHeaderRow(columns []csvColumn) []string {
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = column.header
+	}
+	return headers
+}
+
+func (h *RSSHandler) csvDataRow(headline shared.RssHeadline, columns []csvColumn) []string {
+	row := make([]string, len(columns))
+	for i, column := range columns {
+		row[i] = h.sanitizeCSVField(column.value(headline))
+	}
+	return row
+}
+
+// publishedDateColumn reduces the RFC3339 timestamp to its ISO date for spreadsheet grouping.
+func publishedDateColumn(headline shared.RssHeadline) string {
+	parsed, err := time.Parse(time.RFC3339, headline.PublishedAt)
+	if err != nil {
+		return ""
+	}
+	return shared.FormatDate(parsed)
+}
+
+func (h *RSSHandler) exportAsCSV(c *gin.Context, headlines []shared.RssHeadline, params *exportParams, filename string) {
+	if params.stream {
+		h.streamCSV(c, headlines, params, filename)
+		return
+	}
+	columns := params.columns
+
+	// Build CSV content in memory to calculate Content-Length
+	var buf bytes.Buffer
+	if params.bom {
+		buf.WriteString(utf8BOM)
+	}
+	writer := newCSVWriter(&buf, params)
+
+	// Write header unless header=false
+	if params.header {
+		if err := writer.Write(csvHeaderRow(columns)); err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error: "Failed to write CSV headers",
+			})
+			return
+		}
+	}
+
+	// Write data rows with sanitization
+	for _, headline := range headlines {
+		row := h.csvDataRow(headline, columns)
+		if err := writer.Write(row); err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error: "Failed to write CSV row",
+			})
+			return
+		}
+	}
+
+	writer.Flush()
+
+	// Check for any errors in CSV writer
+	if err := writer.Error(); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to generate CSV",
+		})
+		return
+	}
+
+	// Set headers including Content-Length
+	setCSVHeaders(c, filename, params.format)
+	c.Header("Content-Length", fmt.Sprintf("%d", buf.Len()))
+
+	// Write the response
+	c.Data(http.StatusOK, delimitedContentTypes[params.format], buf.Bytes())
+}
+
+// streamCSV writes rows straight to the response with chunked transfer encoding,
+// flushing periodically so large exports never sit in memory as a whole.
+func (h *RSSHandler) streamCSV(c *gin.Context, headlines []shared.RssHeadline, params *exportParams, filename string) {
+	columns := params.columns
+	setCSVHeaders(c, filename, params.format)
+	c.Status(http.StatusOK)
+
+	if params.bom {
+		_, _ = c.Writer.WriteString(utf8BOM)
+	}
+	writer := newCSVWriter(c.Writer, params)
+	if params.header {
+		_ = writer.Write(csvHeaderRow(columns))
+	}
+
+	for i, headline := range headlines {
+		if err := writer.Write(h.csvDataRow(headline, columns)); err != nil {
+			break
+		}
+		if (i+1)%csvStreamFlushRows == 0 {
+			writer.Flush()
+			c.Writer.Flush()
+		}
+	}
+
+	writer.Flush()
+	c.Writer.Flush()
+
+	// Headers are already sent, so a mid-stream failure can only be recorded.
+	if err := writer.Error(); err != nil {
+		_ = c.Error(fmt.Errorf("streaming CSV export failed: %w", err))
+	}
+}
+
+func newCSVWriter(w io.Writer, params *exportParams) *csv.Writer {
+	writer := csv.NewWriter(w)
+	writer.Comma = params.delimiter
+	return writer
+}
+
+// setCSVHeaders sets the download headers for a csv or tsv export.
+func setCSVHeaders(c *gin.Context, filename, format string) {
+	c.Header("Content-Type", delimitedContentTypes[format])
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	c.Header("X-CSV-Schema-Version", csvSchemaVersion)
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("Content-Security-Policy", "default-src 'none'")
+}
+
+// sanitizeCSVField protects against CSV injection by sanitizing field values.
+// It prefixes potentially dangerous characters with a single quote to neutralize
+// formula injection attempts.
+func (h *RSSHandler) sanitizeCSVField(field string) string {
+	if field == "" {
+		return field
+	}
+
+	// Check if the field starts with a potentially dangerous character
+	// These characters can trigger formula execution in spreadsheet applications
+	dangerousChars := []rune{'=', '+', '-', '@', '\t', '\r'}
+	firstChar := rune(field[0])
+
+	for _, dangerous := range dangerousChars {
+		if firstChar == dangerous {
+			// Prefix with single quote to neutralize formula injection
+			return "'" + field
+		}
+	}
+
+	return field
+}
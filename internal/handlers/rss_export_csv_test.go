@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/f00b455/golang-template/pkg/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseCSVBody parses an export response body into records.
+func parseCSVBody(t *testing.T, body string) [][]string {
+	t.Helper()
+	records, err := csv.NewReader(strings.NewReader(body)).ReadAll()
+	require.NoError(t, err)
+	return records
+}
+
+func TestRSSHandler_ExportCSV_DefaultColumnsAreStable(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+
+	w := doExportRequest(handler, "format=csv")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, csvSchemaVersion, w.Header().Get("X-CSV-Schema-Version"))
+
+	records := parseCSVBody(t, w.Body.String())
+	assert.Equal(t, []string{"Title", "Link", "Published_At", "Source"}, records[0])
+	for _, record := range records[1:] {
+		assert.Len(t, record, 4)
+	}
+}
+
+func TestRSSHandler_ExportCSV_ExtendedColumnsOnlyWhenRequested(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+
+	w := doExportRequest(handler, "format=csv&columns=published_date")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	records := parseCSVBody(t, w.Body.String())
+	assert.Equal(t, []string{"Title", "Link", "Published_At", "Source", "Published_Date"}, records[0])
+	assert.Equal(t, "2023-09-24", records[1][4])
+}
+
+func TestRSSHandler_ExportCSV_CategoriesColumn(t *testing.T) {
+	handler := newExportTestHandler(t, mockRSSWithCategories)
+
+	w := doExportRequest(handler, "format=csv&columns=categories,published_date")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	records := parseCSVBody(t, w.Body.String())
+	assert.Equal(t, []string{"Title", "Link", "Published_At", "Source", "Published_Date", "Categories"}, records[0])
+	assert.Equal(t, "Politik;Ausland", records[1][5])
+	assert.Equal(t, "", records[3][5], "headlines without categories get an empty cell")
+}
+
+func TestRSSHandler_ExportCSV_ImageURLColumn(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+	handler.multiCache = &multiCacheEntry{
+		data: []shared.RssHeadline{
+			{Title: "Mit Bild", Link: "https://www.spiegel.de/1", ImageURL: "https://cdn.spiegel.de/thumb.jpg"},
+			{Title: "Ohne Bild", Link: "https://www.spiegel.de/2"},
+		},
+		timestamp: time.Now(),
+	}
+
+	w := doExportRequest(handler, "format=csv&columns=image_url")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	records := parseCSVBody(t, w.Body.String())
+	assert.Equal(t, []string{"Title", "Link", "Published_At", "Source", "Image_URL"}, records[0])
+	assert.Equal(t, "https://cdn.spiegel.de/thumb.jpg", records[1][4])
+	assert.Equal(t, "", records[2][4])
+}
+
+func TestRSSHandler_ExportCSV_UnknownColumnRejected(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+
+	w := doExportRequest(handler, "format=csv&columns=author")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "valid extended columns are published_date")
+}
+
+func TestRSSHandler_ExportCSV_Streaming(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+
+	headlines := make([]shared.RssHeadline, maxExportItems)
+	for i := range headlines {
+		headlines[i] = shared.RssHeadline{
+			Title:       fmt.Sprintf("Streamed, headline %d", i+1),
+			Link:        fmt.Sprintf("https://www.spiegel.de/%d", i+1),
+			PublishedAt: "2023-09-24T10:00:00Z",
+			Source:      "SPIEGEL",
+		}
+	}
+	handler.multiCache = &multiCacheEntry{data: headlines, timestamp: time.Now()}
+
+	w := doExportRequest(handler, "format=csv&stream=true&limit=1000")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Length"))
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/csv")
+
+	records := parseCSVBody(t, w.Body.String())
+	require.Len(t, records, maxExportItems+1)
+	assert.Equal(t, []string{"Title", "Link", "Published_At", "Source"}, records[0])
+	assert.Equal(t, "Streamed, headline 1000", records[maxExportItems][0])
+}
+
+func TestRSSHandler_ExportCSV_StreamingSanitizesFields(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+	handler.multiCache = &multiCacheEntry{
+		data:      []shared.RssHeadline{{Title: "=HYPERLINK(\"evil\")", Link: "https://example.com"}},
+		timestamp: time.Now(),
+	}
+
+	w := doExportRequest(handler, "format=csv&stream=true")
+
+	records := parseCSVBody(t, w.Body.String())
+	assert.Equal(t, "'=HYPERLINK(\"evil\")", records[1][0])
+}
+
+func TestRSSHandler_Export_FieldsRestrictCSVColumns(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+
+	w := doExportRequest(handler, "format=csv&fields=link,title")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	records := parseCSVBody(t, w.Body.String())
+	assert.Equal(t, []string{"Title", "Link"}, records[0])
+	assert.Equal(t, []string{"Headline 1", "https://www.spiegel.de/1"}, records[1])
+}
+
+func TestRSSHandler_Export_FieldsProjectJSONKeys(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+
+	w := doExportRequest(handler, "format=json&fields=title,published_at")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Headlines []map[string]interface{} `json:"headlines"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotEmpty(t, response.Headlines)
+	assert.Equal(t, map[string]interface{}{
+		"title":       "Headline 1",
+		"publishedAt": "2023-09-24T10:00:00Z",
+	}, response.Headlines[0])
+}
+
+func TestRSSHandler_Export_InvalidFieldRejected(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+
+	w := doExportRequest(handler, "format=csv&fields=title,author")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "invalid fields parameter: valid fields are title,link,published_at,source", response.Error)
+}
+
+func TestRSSHandler_ExportCSV_Delimiters(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		delimiter rune
+	}{
+		{name: "default comma", query: "", delimiter: ','},
+		{name: "comma", query: "&delimiter=comma", delimiter: ','},
+		{name: "semicolon", query: "&delimiter=semicolon", delimiter: ';'},
+		{name: "tab", query: "&delimiter=tab", delimiter: '\t'},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newExportTestHandler(t, MockRSSResponse)
+			handler.multiCache = &multiCacheEntry{
+				data: []shared.RssHeadline{{
+					Title:       "Wahl, Umfrage und Ergebnis",
+					Link:        "https://www.spiegel.de/1",
+					PublishedAt: "2023-09-24T10:00:00Z",
+					Source:      "SPIEGEL",
+				}},
+				timestamp: time.Now(),
+			}
+
+			w := doExportRequest(handler, "format=csv"+tt.query)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Header().Get("Content-Type"), "text/csv")
+
+			reader := csv.NewReader(strings.NewReader(w.Body.String()))
+			reader.Comma = tt.delimiter
+			records, err := reader.ReadAll()
+			require.NoError(t, err)
+			require.Len(t, records, 2)
+			assert.Len(t, records[1], 4)
+			assert.Equal(t, "Wahl, Umfrage und Ergebnis", records[1][0])
+		})
+	}
+}
+
+func TestRSSHandler_ExportTSV(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+	handler.multiCache = &multiCacheEntry{
+		data:      []shared.RssHeadline{{Title: "=cmd|' /C calc'!A0", Link: "https://www.spiegel.de/1"}},
+		timestamp: time.Now(),
+	}
+
+	w := doExportRequest(handler, "format=tsv")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/tab-separated-values; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), ".tsv\"")
+
+	lines := strings.Split(w.Body.String(), "\n")
+	assert.Equal(t, "Title\tLink\tPublished_At\tSource", lines[0])
+
+	reader := csv.NewReader(strings.NewReader(w.Body.String()))
+	reader.Comma = '\t'
+	records, err := reader.ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, "'=cmd|' /C calc'!A0", records[1][0])
+}
+
+func TestRSSHandler_ExportCSV_SemicolonKeepsSanitization(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+	handler.multiCache = &multiCacheEntry{
+		data:      []shared.RssHeadline{{Title: "=SUM(A1;A2)", Link: "https://example.com"}},
+		timestamp: time.Now(),
+	}
+
+	w := doExportRequest(handler, "format=csv&delimiter=semicolon")
+
+	reader := csv.NewReader(strings.NewReader(w.Body.String()))
+	reader.Comma = ';'
+	records, err := reader.ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, "'=SUM(A1;A2)", records[1][0])
+	assert.Contains(t, w.Body.String(), "\"'=SUM(A1;A2)\"")
+}
+
+func TestRSSHandler_ExportCSV_WithoutHeader(t *testing.T) {
+	for _, query := range []string{"format=csv&header=false", "format=csv&header=false&stream=true"} {
+		t.Run(query, func(t *testing.T) {
+			handler := newExportTestHandler(t, MockRSSResponse)
+
+			w := doExportRequest(handler, query+"&delimiter=semicolon")
+
+			require.Equal(t, http.StatusOK, w.Code)
+			reader := csv.NewReader(strings.NewReader(w.Body.String()))
+			reader.Comma = ';'
+			records, err := reader.ReadAll()
+			require.NoError(t, err)
+			require.Len(t, records, 6)
+			assert.Equal(t, "Headline 1", records[0][0])
+			assert.NotContains(t, w.Body.String(), "Title")
+		})
+	}
+}
+
+func TestRSSHandler_ExportCSV_InvalidDelimiter(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+
+	w := doExportRequest(handler, "format=csv&delimiter=pipe")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid delimiter parameter")
+}
+
+func TestRSSHandler_ExportCSV_BOM(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantBOM bool
+	}{
+		{name: "bom requested", query: "format=csv&bom=true", wantBOM: true},
+		{name: "bom requested when streaming", query: "format=csv&bom=true&stream=true", wantBOM: true},
+		{name: "default without bom", query: "format=csv", wantBOM: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newExportTestHandler(t, MockRSSResponse)
+			handler.multiCache = &multiCacheEntry{
+				data:      []shared.RssHeadline{{Title: "Schöne Grüße", Link: "https://www.spiegel.de/1"}},
+				timestamp: time.Now(),
+			}
+
+			w := doExportRequest(handler, tt.query)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			body := w.Body.Bytes()
+			if tt.wantBOM {
+				assert.Equal(t, []byte{0xEF, 0xBB, 0xBF}, body[:3])
+			} else {
+				assert.NotEqual(t, []byte{0xEF, 0xBB, 0xBF}, body[:3])
+				assert.True(t, strings.HasPrefix(string(body), "Title,"))
+			}
+			if length := w.Header().Get("Content-Length"); length != "" {
+				assert.Equal(t, fmt.Sprintf("%d", len(body)), length)
+			}
+		})
+	}
+}
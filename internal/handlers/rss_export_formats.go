@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/f00b455/golang-template/pkg/shared"
+	"github.com/gin-gonic/gin"
+)
+
+// rssDocument is the RSS 2.0 envelope used when re-exporting headlines as a feed.
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	Items       []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title   cdataText  `xml:"title"`
+	Link    string     `xml:"link"`
+	PubDate string     `xml:"pubDate,omitempty"`
+	Source  *rssSource `xml:"source,omitempty"`
+}
+
+// rssSource names the channel an item came from; RSS 2.0 requires its url attribute.
+type rssSource struct {
+	URL  string `xml:"url,attr"`
+	Name string `xml:",chardata"`
+}
+
+// cdataText is written as a CDATA section, the way feeds usually carry titles,
+// so characters like & and < survive without entity escaping.
+type cdataText struct {
+	Text string `xml:",cdata"`
+}
+
+// buildRSSDocument converts headlines into an RSS 2.0 document so the export can be
+// re-syndicated. The channel link and each item's <source url> point at feedURL,
+// the feed the headlines were read from; items without a source name get no <source>.
+func buildRSSDocument(headlines []shared.RssHeadline, feedURL string) rssDocument {
+	items := make([]rssItem, 0, len(headlines))
+	for _, headline := range headlines {
+		item := rssItem{
+			Title:   cdataText{Text: headline.Title},
+			Link:    headline.Link,
+			PubDate: formatRSSPubDate(headline.PublishedAt),
+		}
+		if headline.Source != "" {
+			item.Source = &rssSource{URL: feedURL, Name: headline.Source}
+		}
+		items = append(items, item)
+	}
+
+	return rssDocument{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       "RSS Export",
+			Link:        feedURL,
+			Description: "Re-exported RSS headlines",
+			Items:       items,
+		},
+	}
+}
+
+// formatRSSPubDate converts the stored RFC3339 timestamp back into the RFC1123Z form RSS expects.
+func formatRSSPubDate(publishedAt string) string {
+	parsed, err := time.Parse(time.RFC3339, publishedAt)
+	if err != nil {
+		return ""
+	}
+	return parsed.Format(time.RFC1123Z)
+}
+
+func (h *RSSHandler) exportAsRSS(c *gin.Context, headlines []shared.RssHeadline, filename string) {
+	body, err := xml.MarshalIndent(buildRSSDocument(headlines, h.cfg.SpiegelRSSURL), "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to generate RSS",
+		})
+		return
+	}
+	body = append([]byte(xml.Header), body...)
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("Content-Security-Policy", "default-src 'none'")
+	c.Data(http.StatusOK, "application/rss+xml; charset=utf-8", body)
+}
+
+// jsonFeedVersion identifies the JSON Feed spec the export follows.
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+// jsonFeedDocument is a JSON Feed 1.1 document (https://www.jsonfeed.org/version/1.1/).
+type jsonFeedDocument struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string `json:"id"`
+	URL           string `json:"url,omitempty"`
+	Title         string `json:"title,omitempty"`
+	DatePublished string `json:"date_published,omitempty"`
+}
+
+// buildJSONFeedDocument converts headlines into a JSON Feed document. Headlines
+// carry no GUID, so the link doubles as the item id, as the spec suggests.
+func buildJSONFeedDocument(headlines []shared.RssHeadline, homePageURL string) jsonFeedDocument {
+	items := make([]jsonFeedItem, 0, len(headlines))
+	for _, headline := range headlines {
+		items = append(items, jsonFeedItem{
+			ID:            headline.Link,
+			URL:           headline.Link,
+			Title:         headline.Title,
+			DatePublished: headline.PublishedAt,
+		})
+	}
+
+	return jsonFeedDocument{
+		Version:     jsonFeedVersion,
+		Title:       "RSS Export",
+		HomePageURL: homePageURL,
+		Items:       items,
+	}
+}
+
+func (h *RSSHandler) exportAsJSONFeed(c *gin.Context, headlines []shared.RssHeadline, filename string) {
+	body, err := json.Marshal(buildJSONFeedDocument(headlines, h.cfg.SpiegelRSSURL))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to generate JSON Feed",
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("Content-Security-Policy", "default-src 'none'")
+	c.Data(http.StatusOK, "application/feed+json; charset=utf-8", body)
+}
+
+// markdownTextEscaper escapes characters that would otherwise be interpreted as Markdown syntax in link text.
+var markdownTextEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"[", `\[`,
+	"]", `\]`,
+	"*", `\*`,
+	"_", `\_`,
+	"`", "\\`",
+	"<", `\<`,
+	">", `\>`,
+)
+
+// markdownURLEscaper percent-encodes characters that would terminate a Markdown link destination early.
+var markdownURLEscaper = strings.NewReplacer(
+	" ", "%20",
+	"(", "%28",
+	")", "%29",
+	"<", "%3C",
+	">", "%3E",
+)
+
+// buildMarkdownDigest renders headlines as a Markdown bullet list suitable for pasting into notes.
+func buildMarkdownDigest(headlines []shared.RssHeadline, filter string, exportDate time.Time) string {
+	var builder strings.Builder
+	builder.WriteString("# RSS Export\n\n")
+	builder.WriteString(fmt.Sprintf("Exported: %s\n", exportDate.Format(time.RFC3339)))
+	if filter != "" {
+		builder.WriteString(fmt.Sprintf("Filter: %s\n", markdownTextEscaper.Replace(filter)))
+	}
+	builder.WriteString("\n")
+
+	for _, headline := range headlines {
+		builder.WriteString(formatMarkdownLine(headline))
+	}
+	return builder.String()
+}
+
+func formatMarkdownLine(headline shared.RssHeadline) string {
+	return fmt.Sprintf("- [%s](%s) — %s (%s)\n",
+		markdownTextEscaper.Replace(headline.Title),
+		markdownURLEscaper.Replace(headline.Link),
+		formatMarkdownDate(headline.PublishedAt),
+		markdownTextEscaper.Replace(headline.Source),
+	)
+}
+
+func formatMarkdownDate(publishedAt string) string {
+	parsed, err := time.Parse(time.RFC3339, publishedAt)
+	if err != nil {
+		return publishedAt
+	}
+	return parsed.UTC().Format("2006-01-02 15:04 MST")
+}
+
+func (h *RSSHandler) exportAsMarkdown(c *gin.Context, headlines []shared.RssHeadline, filter, filename string) {
+	body := buildMarkdownDigest(headlines, filter, time.Now())
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("Content-Security-Policy", "default-src 'none'")
+	c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(body))
+}
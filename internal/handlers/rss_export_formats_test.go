@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/f00b455/golang-template/pkg/shared"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newExportTestHandler returns a handler backed by a mock feed serving rssContent.
+func newExportTestHandler(t *testing.T, rssContent string) *RSSHandler {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	server := SetupMockServer(rssContent, http.StatusOK)
+	t.Cleanup(server.Close)
+
+	handler := NewRSSHandler()
+	handler.cfg.SpiegelRSSURL = server.URL
+	handler.ResetCache()
+	return handler
+}
+
+// doExportRequest calls ExportHeadlines with the given raw query string.
+func doExportRequest(handler *RSSHandler, query string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/rss/spiegel/export?"+query, nil)
+
+	handler.ExportHeadlines(c)
+	return w
+}
+
+func TestRSSHandler_ExportHeadlines_RSS(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+
+	w := doExportRequest(handler, "format=rss&limit=3")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/rss+xml")
+	assert.Contains(t, w.Header().Get("Content-Disposition"), ".xml")
+
+	var doc rssDocument
+	require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "2.0", doc.Version)
+	require.Len(t, doc.Channel.Items, 3)
+	assert.Equal(t, "Sun, 24 Sep 2023 10:00:00 +0000", doc.Channel.Items[0].PubDate)
+	require.NotNil(t, doc.Channel.Items[0].Source)
+	assert.Equal(t, "SPIEGEL ONLINE", doc.Channel.Items[0].Source.Name)
+	assert.Equal(t, handler.cfg.SpiegelRSSURL, doc.Channel.Items[0].Source.URL)
+	assert.Contains(t, w.Body.String(), `<source url="`+handler.cfg.SpiegelRSSURL+`">SPIEGEL ONLINE</source>`)
+	assert.Equal(t, "Headline 1", doc.Channel.Items[0].Title.Text)
+	assert.Contains(t, w.Body.String(), "<title><![CDATA[Headline 1]]></title>")
+}
+
+func TestRSSHandler_ExportHeadlines_RSSTitlesUseCDATA(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+	handler.multiCache = &multiCacheEntry{
+		data: []shared.RssHeadline{{
+			Title:       "Bund & Länder <live>",
+			Link:        "https://www.spiegel.de/1",
+			PublishedAt: "2023-09-24T10:00:00Z",
+		}},
+		timestamp: time.Now(),
+	}
+
+	w := doExportRequest(handler, "format=rss")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "<![CDATA[Bund & Länder <live>]]>")
+
+	reparsed := handler.parseMultipleRSSItems(w.Body.String(), maxReturnItems)
+	require.Len(t, reparsed, 1)
+	assert.Equal(t, "Bund & Länder <live>", reparsed[0].Title)
+}
+
+func TestRSSHandler_ExportHeadlines_RSSRoundTrip(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+
+	w := doExportRequest(handler, "format=rss&filter=Headline")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	reparsed := handler.parseMultipleRSSItems(w.Body.String(), maxReturnItems)
+
+	original, _ := handler.getCachedHeadlines()
+	require.Len(t, reparsed, len(original))
+	for i := range original {
+		assert.Equal(t, original[i].Title, reparsed[i].Title)
+		assert.Equal(t, original[i].Link, reparsed[i].Link)
+		assert.Equal(t, original[i].PublishedAt, reparsed[i].PublishedAt)
+	}
+}
+
+func TestRSSHandler_ExportHeadlines_Markdown(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+
+	w := doExportRequest(handler, "format=md&limit=2")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/markdown")
+	assert.Contains(t, w.Header().Get("Content-Disposition"), ".md")
+
+	body := w.Body.String()
+	assert.True(t, strings.HasPrefix(body, "# RSS Export\n"))
+	assert.Contains(t, body, "Exported: ")
+	assert.Contains(t, body, "- [Headline 1](https://www.spiegel.de/1) — 2023-09-24 10:00 UTC (SPIEGEL ONLINE)\n")
+	assert.Equal(t, 2, strings.Count(body, "\n- ["))
+}
+
+func TestRSSHandler_ExportHeadlines_JSONFeed(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+
+	w := doExportRequest(handler, "format=jsonfeed&limit=2")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/feed+json")
+	assert.Contains(t, w.Header().Get("Content-Disposition"), ".json")
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "https://jsonfeed.org/version/1.1", doc["version"])
+	assert.Equal(t, "RSS Export", doc["title"])
+
+	items, ok := doc["items"].([]interface{})
+	require.True(t, ok, "items must be an array")
+	require.Len(t, items, 2)
+	item, ok := items[0].(map[string]interface{})
+	require.True(t, ok, "each item must be an object")
+	assert.Equal(t, "https://www.spiegel.de/1", item["id"])
+	assert.Equal(t, "https://www.spiegel.de/1", item["url"])
+	assert.Equal(t, "Headline 1", item["title"])
+	assert.Equal(t, "2023-09-24T10:00:00Z", item["date_published"])
+}
+
+func TestBuildRSSDocument_OmitsSourceWithoutName(t *testing.T) {
+	doc := buildRSSDocument([]shared.RssHeadline{{Title: "Headline", Link: "https://www.spiegel.de/1"}}, "https://feed.test/rss")
+
+	body, err := xml.Marshal(doc)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), "<source")
+}
+
+func TestBuildMarkdownDigest_EscapesSpecialCharacters(t *testing.T) {
+	headlines := []shared.RssHeadline{{
+		Title:       "[Eilmeldung] *Breaking* news",
+		Link:        "https://example.com/a_(b)",
+		PublishedAt: "2023-09-24T10:00:00Z",
+		Source:      "SPIEGEL",
+	}}
+
+	body := buildMarkdownDigest(headlines, "", time.Date(2023, 9, 24, 12, 0, 0, 0, time.UTC))
+
+	assert.Contains(t, body, `- [\[Eilmeldung\] \*Breaking\* news](https://example.com/a_%28b%29)`)
+	assert.Contains(t, body, "Exported: 2023-09-24T12:00:00Z")
+}
+
+func TestRSSHandler_ExportHeadlines_CountHeaders(t *testing.T) {
+	for _, format := range []string{"json", "csv"} {
+		t.Run(format, func(t *testing.T) {
+			handler := newExportTestHandler(t, MockRSSResponse)
+
+			w := doExportRequest(handler, "format="+format+"&limit=4")
+			require.Equal(t, http.StatusOK, w.Code)
+
+			items, err := strconv.Atoi(w.Header().Get("X-Total-Items"))
+			require.NoError(t, err, "X-Total-Items should be numeric")
+			assert.Equal(t, 4, items)
+			assert.Equal(t, format, w.Header().Get("X-Export-Format"))
+
+			length, err := strconv.Atoi(w.Header().Get("Content-Length"))
+			require.NoError(t, err, "Content-Length should be numeric")
+			assert.Equal(t, w.Body.Len(), length)
+		})
+	}
+}
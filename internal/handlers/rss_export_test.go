@@ -183,7 +183,7 @@ func TestRSSHandler_ExportHeadlines_Errors(t *testing.T) {
 			name:           "Invalid format",
 			format:         "xml",
 			expectedStatus: http.StatusBadRequest,
-			expectedError:  "invalid format parameter: must be 'json' or 'csv'",
+			expectedError:  "invalid format parameter: must be one of json, csv, tsv, rss, md, jsonfeed",
 		},
 		{
 			name:           "Missing format",
@@ -195,7 +195,7 @@ func TestRSSHandler_ExportHeadlines_Errors(t *testing.T) {
 			name:           "Invalid format with special chars",
 			format:         "invalid_format",
 			expectedStatus: http.StatusBadRequest,
-			expectedError:  "invalid format parameter: must be 'json' or 'csv'",
+			expectedError:  "invalid format parameter: must be one of json, csv, tsv, rss, md, jsonfeed",
 		},
 	}
 
@@ -405,4 +405,45 @@ func TestRSSHandler_ExportHeadlines_LimitValidation(t *testing.T) {
 			assert.Equal(t, tt.expectedCount, len(response.Headlines))
 		})
 	}
-}
\ No newline at end of file
+}
+func TestRSSHandler_ExportHeadlines_ConcurrencyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("EXPORT_MAX_CONCURRENT", "1")
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Header().Set("Content-Type", "application/rss+xml")
+		_, _ = w.Write([]byte(MockRSSResponse))
+	}))
+	defer server.Close()
+
+	handler := NewRSSHandler()
+	handler.cfg.SpiegelRSSURL = server.URL
+	handler.ResetCache()
+
+	// The first export holds the only slot while its upstream fetch is blocked.
+	firstDone := make(chan *httptest.ResponseRecorder)
+	go func() {
+		firstDone <- doExportRequest(handler, "format=json")
+	}()
+	<-started
+
+	w := doExportRequest(handler, "format=json")
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, strconv.Itoa(exportRetryAfterSeconds), w.Header().Get("Retry-After"))
+
+	var response ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Contains(t, response.Error, "Too many concurrent exports")
+
+	close(release)
+	first := <-firstDone
+	assert.Equal(t, http.StatusOK, first.Code)
+
+	// The slot is released once the first export completes.
+	assert.Equal(t, http.StatusOK, doExportRequest(handler, "format=json").Code)
+}
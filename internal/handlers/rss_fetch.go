@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/f00b455/golang-template/pkg/shared"
+)
+
+// fetchLatestHeadline returns the feed's first headline and the number of headlines
+// in the feed, counted the same way as the top5 totalCount. It goes through
+// fetchAndCacheHeadlines, so it shares the cached headlines, concurrent fetches,
+// conditional requests and the minimum fetch interval with top5.
+func (h *RSSHandler) fetchLatestHeadline(ctx context.Context) (*shared.RssHeadline, int, error) {
+	headlines, err := h.fetchAndCacheHeadlines(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(headlines) == 0 {
+		return nil, 0, ErrParse
+	}
+
+	latest := headlines[0]
+	return &latest, len(headlines), nil
+}
+
+func (h *RSSHandler) fetchMultipleHeadlines(ctx context.Context, limit int) ([]shared.RssHeadline, error) {
+	rssText, err := h.fetchRSSFeed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.parseMultipleRSSItems(rssText, limit), nil
+}
+
+// feedValidators are the Last-Modified and ETag values the upstream at url sent
+// with a feed. Sent back on the next fetch of url they let it answer 304.
+type feedValidators struct {
+	url          string
+	lastModified string
+	etag         string
+}
+
+// isSet reports whether there is a validator to send.
+func (v feedValidators) isSet() bool {
+	return v.lastModified != "" || v.etag != ""
+}
+
+// feedResult is a downloaded feed. When notModified is set the upstream
+// answered 304 and body is empty.
+type feedResult struct {
+	body        string
+	validators  feedValidators
+	notModified bool
+}
+
+// fetchRSSFeed downloads the feed through the circuit breaker, failing fast with
+// ErrCircuitOpen while the feed is considered down.
+func (h *RSSHandler) fetchRSSFeed(ctx context.Context) (string, error) {
+	result, err := h.fetchRSSFeedIfModified(ctx, feedValidators{})
+	return result.body, err
+}
+
+// fetchRSSFeedIfModified downloads the feed through the circuit breaker, sending
+// the cached validators so an unchanged feed is answered with 304. The feed URLs
+// are tried in order until one succeeds; when all fail the last error is
+// returned and counted once by the breaker. A fetch abandoned because ctx was
+// cancelled says nothing about the feed and is not counted as a failure.
+func (h *RSSHandler) fetchRSSFeedIfModified(ctx context.Context, cached feedValidators) (feedResult, error) {
+	if !h.breaker.allow() {
+		return feedResult{}, ErrCircuitOpen
+	}
+
+	feedURLs := h.feedURLs()
+	var lastErr error
+	for i, feedURL := range feedURLs {
+		result, err := h.fetchFeedWithRetry(ctx, h.httpClient, feedURL, cached)
+		if err == nil {
+			h.breaker.recordSuccess()
+			return result, nil
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			h.breaker.abandon()
+			return feedResult{}, err
+		}
+		if i < len(feedURLs)-1 {
+			log.Printf("Fetching feed %s failed, trying %s: %v", feedURL, feedURLs[i+1], err)
+		}
+	}
+
+	h.breaker.recordFailure()
+	return feedResult{}, lastErr
+}
+
+// feedURLs lists SPIEGEL_RSS_URL followed by its fallbacks in the order they are tried.
+func (h *RSSHandler) feedURLs() []string {
+	return append([]string{h.cfg.SpiegelRSSURL}, h.cfg.SpiegelRSSFallbackURLs...)
+}
+
+// fetchFeedWithRetry downloads the feed at feedURL with client, retrying network errors and 5xx
+// responses with exponential backoff. It holds one of the RSS_MAX_CONCURRENT_FETCHES
+// slots while fetching; waiting for the slot and all attempts share one
+// RSS_REQUEST_TIMEOUT budget. When parent is cancelled or its deadline passes the
+// fetch is aborted with parent's error; only running out of the fetch's own
+// budget is reported as ErrUpstreamTimeout.
+func (h *RSSHandler) fetchFeedWithRetry(parent context.Context, client *http.Client, feedURL string, cached feedValidators) (feedResult, error) {
+	if cached.url != feedURL {
+		cached = feedValidators{}
+	}
+
+	ctx, cancel := context.WithTimeout(parent, h.cfg.RSSRequestTimeout)
+	defer cancel()
+
+	// Waiting for a free fetch slot counts against the same time budget
+	select {
+	case h.fetchSlots <- struct{}{}:
+		defer func() { <-h.fetchSlots }()
+	case <-ctx.Done():
+		if err := parent.Err(); err != nil {
+			return feedResult{}, err
+		}
+		return feedResult{}, fmt.Errorf("%w after %v", ErrUpstreamTimeout, h.cfg.RSSRequestTimeout)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= h.cfg.RSSRetryCount; attempt++ {
+		if attempt > 0 && !waitForRetry(ctx, h.cfg.RSSRetryBaseDelay<<(attempt-1)) {
+			break
+		}
+
+		result, retryable, err := h.fetchRSSFeedOnce(ctx, client, feedURL, cached)
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+		// An attempt cut short by the end of parent is not an upstream timeout
+		if err := parent.Err(); err != nil {
+			return feedResult{}, err
+		}
+		if !retryable {
+			return feedResult{}, err
+		}
+	}
+
+	if err := parent.Err(); err != nil {
+		return feedResult{}, err
+	}
+	// Running out of time budget between retries is reported as a timeout
+	if ctx.Err() == context.DeadlineExceeded && !errors.Is(lastErr, ErrUpstreamTimeout) {
+		return feedResult{}, fmt.Errorf("%w after %v", ErrUpstreamTimeout, h.cfg.RSSRequestTimeout)
+	}
+	return feedResult{}, lastErr
+}
+
+// fetchRSSFeedOnce performs a single feed request and reports whether a failure may be retried.
+func (h *RSSHandler) fetchRSSFeedOnce(ctx context.Context, client *http.Client, feedURL string, cached feedValidators) (feedResult, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
+	if err != nil {
+		return feedResult{}, false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", h.cfg.RSSAccept)
+	req.Header.Set("User-Agent", h.cfg.RSSUserAgent)
+	// Setting Accept-Encoding ourselves disables the transport's transparent
+	// decompression, so readFeedBody has to handle gzip.
+	req.Header.Set("Accept-Encoding", "gzip")
+	if cached.lastModified != "" {
+		req.Header.Set("If-Modified-Since", cached.lastModified)
+	}
+	if cached.etag != "" {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := client.Do(req)
+	if errors.Is(err, ErrNonPublicSource) {
+		return feedResult{}, false, err
+	}
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded || isTimeout(err) {
+			return feedResult{}, ctx.Err() == nil, fmt.Errorf("%w after %v", ErrUpstreamTimeout, h.cfg.RSSRequestTimeout)
+		}
+		return feedResult{}, true, fmt.Errorf("failed to fetch RSS feed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotModified && cached.isSet() {
+		return feedResult{validators: cached, notModified: true}, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		retryable := resp.StatusCode >= http.StatusInternalServerError
+		return feedResult{}, retryable, fmt.Errorf("%w: status code %d", ErrUpstreamStatus, resp.StatusCode)
+	}
+
+	body, err := readFeedBody(resp, int64(h.cfg.RSSMaxBodyBytes))
+	if errors.Is(err, ErrPayloadTooLarge) {
+		return feedResult{}, false, err
+	}
+	if err != nil {
+		return feedResult{}, true, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return feedResult{
+		body: string(body),
+		validators: feedValidators{
+			url:          feedURL,
+			lastModified: resp.Header.Get("Last-Modified"),
+			etag:         resp.Header.Get("ETag"),
+		},
+	}, false, nil
+}
+
+// readFeedBody reads at most maxBytes of the response body, decompressing it when
+// the feed is gzip-encoded. Larger bodies fail with ErrPayloadTooLarge.
+func readFeedBody(resp *http.Response, maxBytes int64) ([]byte, error) {
+	var reader io.Reader = resp.Body
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = gz.Close() }()
+		reader = gz
+	}
+
+	body, err := io.ReadAll(io.LimitReader(reader, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > maxBytes {
+		return nil, fmt.Errorf("%w: feed exceeds %d bytes", ErrPayloadTooLarge, maxBytes)
+	}
+	return body, nil
+}
+
+// waitForRetry sleeps for delay and reports false if ctx ends first.
+func waitForRetry(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,525 @@
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/f00b455/golang-template/internal/testutil"
+	"github.com/f00b455/golang-template/pkg/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyClient answers each request with the next scripted failure and then with MockRSSResponse.
+func flakyClient(attempts *int32, failures ...func(req *http.Request) (*http.Response, error)) *http.Client {
+	return &http.Client{Transport: &testutil.MockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			n := int(atomic.AddInt32(attempts, 1))
+			if n <= len(failures) {
+				return failures[n-1](req)
+			}
+			return statusResponse(req, http.StatusOK, MockRSSResponse), nil
+		},
+	}}
+}
+
+func statusResponse(req *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       testutil.CreateReadCloser(body),
+		Header:     make(http.Header),
+		Request:    req,
+	}
+}
+
+func newRetryTestHandler(client *http.Client) *RSSHandler {
+	handler := NewRSSHandlerWithClient(client)
+	handler.cfg.SpiegelRSSURL = "http://feed.test/rss"
+	handler.cfg.RSSRetryCount = 2
+	handler.cfg.RSSRetryBaseDelay = time.Millisecond
+	return handler
+}
+
+func TestFetchRSSFeed_RetriesTransientFailures(t *testing.T) {
+	var attempts int32
+	handler := newRetryTestHandler(flakyClient(&attempts,
+		func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection reset by peer")
+		},
+		func(req *http.Request) (*http.Response, error) {
+			return statusResponse(req, http.StatusBadGateway, ""), nil
+		},
+	))
+
+	body, err := handler.fetchRSSFeed(context.Background())
+
+	require.NoError(t, err)
+	assert.Contains(t, body, "Headline 1")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+}
+
+func TestFetchRSSFeed_DoesNotRetryClientErrors(t *testing.T) {
+	var attempts int32
+	handler := newRetryTestHandler(flakyClient(&attempts,
+		func(req *http.Request) (*http.Response, error) {
+			return statusResponse(req, http.StatusNotFound, ""), nil
+		},
+	))
+
+	_, err := handler.fetchRSSFeed(context.Background())
+
+	require.ErrorIs(t, err, ErrUpstreamStatus)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+}
+
+func TestFetchRSSFeed_GivesUpAfterRetryCount(t *testing.T) {
+	var attempts int32
+	serverError := func(req *http.Request) (*http.Response, error) {
+		return statusResponse(req, http.StatusInternalServerError, ""), nil
+	}
+	handler := newRetryTestHandler(flakyClient(&attempts, serverError, serverError, serverError, serverError))
+
+	_, err := handler.fetchRSSFeed(context.Background())
+
+	require.ErrorIs(t, err, ErrUpstreamStatus)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+}
+
+func TestFetchRSSFeed_DecompressesGzip(t *testing.T) {
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, err := gz.Write([]byte(MockRSSResponse))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	client := &http.Client{Transport: &testutil.MockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "gzip", req.Header.Get("Accept-Encoding"))
+			resp := statusResponse(req, http.StatusOK, "")
+			resp.Header.Set("Content-Encoding", "gzip")
+			resp.Body = io.NopCloser(bytes.NewReader(compressed.Bytes()))
+			return resp, nil
+		},
+	}}
+	handler := newRetryTestHandler(client)
+
+	headlines, err := handler.fetchMultipleHeadlines(context.Background(), handler.cfg.RSSMaxFetchItems)
+	require.NoError(t, err)
+	require.Len(t, headlines, 6)
+	assert.Equal(t, "Headline 1", headlines[0].Title)
+}
+
+func TestFetchAndCacheHeadlines_CachesUpToMaxFetchItems(t *testing.T) {
+	feed := generateLargeRSSFeed(600)
+	client := &http.Client{Transport: &testutil.MockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			return statusResponse(req, http.StatusOK, feed), nil
+		},
+	}}
+	handler := NewRSSHandlerWithClient(client)
+	handler.cfg.RSSMaxFetchItems = 500
+
+	headlines, err := handler.fetchAndCacheHeadlines(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, headlines, 500)
+	assert.Len(t, handler.multiCache.data, 500)
+	assert.Equal(t, "News Item 500: Important Headlines Today", handler.multiCache.data[499].Title)
+}
+
+func TestFetchAndCacheHeadlines_MinFetchInterval(t *testing.T) {
+	tests := []struct {
+		name          string
+		interval      time.Duration
+		wantUpstreams int32
+	}{
+		{name: "fetches at most once within the interval", interval: 30 * time.Second, wantUpstreams: 1},
+		{name: "zero interval disables the guard", interval: 0, wantUpstreams: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var upstreams int32
+			client := &http.Client{Transport: &testutil.MockTransport{
+				RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+					atomic.AddInt32(&upstreams, 1)
+					return statusResponse(req, http.StatusOK, MockRSSResponse), nil
+				},
+			}}
+			handler := NewRSSHandlerWithClient(client)
+			handler.cfg.RSSMinFetchInterval = tt.interval
+
+			for i := 0; i < 5; i++ {
+				handler.ResetCache()
+				headlines, err := handler.fetchAndCacheHeadlines(context.Background())
+				require.NoError(t, err)
+				require.Len(t, headlines, 6, "every cache miss is served the last fetched headlines")
+			}
+
+			assert.Equal(t, tt.wantUpstreams, atomic.LoadInt32(&upstreams))
+		})
+	}
+}
+
+// endlessReader yields an unbounded stream of bytes, like a misbehaving feed.
+type endlessReader struct{}
+
+func (endlessReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 'x'
+	}
+	return len(p), nil
+}
+
+func TestFetchRSSFeed_RejectsOversizedBody(t *testing.T) {
+	var attempts int32
+	client := &http.Client{Transport: &testutil.MockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&attempts, 1)
+			resp := statusResponse(req, http.StatusOK, "")
+			resp.Body = io.NopCloser(endlessReader{})
+			return resp, nil
+		},
+	}}
+	handler := newRetryTestHandler(client)
+	handler.cfg.RSSMaxBodyBytes = 1024
+
+	_, err := handler.fetchRSSFeed(context.Background())
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrPayloadTooLarge))
+	assert.Equal(t, CodePayloadTooLarge, upstreamErrorCode(err))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts), "oversized feeds are not retried")
+}
+
+func TestFetchAndCacheHeadlines_ConcurrentMissesShareOneFetch(t *testing.T) {
+	var upstreamCalls int32
+	client := &http.Client{Transport: &testutil.MockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&upstreamCalls, 1)
+			// Keep the fetch in flight long enough for every request to miss the cache.
+			time.Sleep(100 * time.Millisecond)
+			return statusResponse(req, http.StatusOK, MockRSSResponse), nil
+		},
+	}}
+	handler := newRetryTestHandler(client)
+
+	const requests = 50
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	results := make([][]shared.RssHeadline, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			headlines, err := handler.fetchAndCacheHeadlines(context.Background())
+			assert.NoError(t, err)
+			results[i] = headlines
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&upstreamCalls))
+	for _, headlines := range results {
+		assert.Len(t, headlines, 6)
+	}
+	// Every caller owns its slice.
+	results[0][0].Title = "changed"
+	assert.Equal(t, "Headline 1", results[1][0].Title)
+}
+
+func TestFetchAndCacheHeadlines_SurvivesCancelledSharedFetch(t *testing.T) {
+	var upstreamCalls int32
+	leaderStarted := make(chan struct{})
+	client := &http.Client{Transport: &testutil.MockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			if atomic.AddInt32(&upstreamCalls, 1) == 1 {
+				// The first fetch hangs until its caller goes away.
+				close(leaderStarted)
+				<-req.Context().Done()
+				return nil, req.Context().Err()
+			}
+			return statusResponse(req, http.StatusOK, MockRSSResponse), nil
+		},
+	}}
+	handler := newRetryTestHandler(client)
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderDone := make(chan error, 1)
+	go func() {
+		_, err := handler.fetchAndCacheHeadlines(leaderCtx)
+		leaderDone <- err
+	}()
+	<-leaderStarted
+
+	followerDone := make(chan []shared.RssHeadline, 1)
+	go func() {
+		headlines, err := handler.fetchAndCacheHeadlines(context.Background())
+		assert.NoError(t, err)
+		followerDone <- headlines
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancelLeader()
+
+	assert.ErrorIs(t, <-leaderDone, context.Canceled)
+	assert.Len(t, <-followerDone, 6, "a waiting caller refetches instead of inheriting the cancellation")
+}
+
+func TestFetchRSSFeed_SendsConfiguredHeaders(t *testing.T) {
+	var sent http.Header
+	client := &http.Client{Transport: &testutil.MockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			sent = req.Header.Clone()
+			return statusResponse(req, http.StatusOK, MockRSSResponse), nil
+		},
+	}}
+	handler := newRetryTestHandler(client)
+	handler.cfg.RSSUserAgent = "FeedBot/2.0 (+https://example.com/bot)"
+	handler.cfg.RSSAccept = "application/atom+xml"
+
+	_, err := handler.fetchRSSFeed(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "FeedBot/2.0 (+https://example.com/bot)", sent.Get("User-Agent"))
+	assert.Equal(t, "application/atom+xml", sent.Get("Accept"))
+}
+
+func TestFetchRSSFeed_LimitsConcurrentFetches(t *testing.T) {
+	t.Setenv("RSS_MAX_CONCURRENT_FETCHES", "3")
+
+	var inFlight, maxInFlight int32
+	release := make(chan struct{})
+	client := &http.Client{Transport: &testutil.MockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			current := atomic.AddInt32(&inFlight, 1)
+			for {
+				seen := atomic.LoadInt32(&maxInFlight)
+				if current <= seen || atomic.CompareAndSwapInt32(&maxInFlight, seen, current) {
+					break
+				}
+			}
+			<-release
+			atomic.AddInt32(&inFlight, -1)
+			return statusResponse(req, http.StatusOK, MockRSSResponse), nil
+		},
+	}}
+	handler := NewRSSHandlerWithClient(client)
+	handler.cfg.RSSRequestTimeout = 5 * time.Second
+
+	// Distinct feeds so singleflight does not merge the fetches
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := handler.fetchFeedWithRetry(context.Background(), handler.httpClient, fmt.Sprintf("http://feed%d.test/rss", i), feedValidators{})
+			assert.NoError(t, err)
+		}(i)
+	}
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&inFlight) == 3
+	}, 2*time.Second, 5*time.Millisecond, "three fetches should run at once")
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&inFlight), "a fourth fetch must wait for a free slot")
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(3), atomic.LoadInt32(&maxInFlight))
+}
+
+// conditionalClient serves MockRSSResponse with validators on the first request
+// and answers every later request carrying them with 304.
+func conditionalClient(requests *[]*http.Request) *http.Client {
+	return &http.Client{Transport: &testutil.MockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			*requests = append(*requests, req)
+			if len(*requests) == 1 {
+				resp := statusResponse(req, http.StatusOK, MockRSSResponse)
+				resp.Header.Set("ETag", `"v1"`)
+				resp.Header.Set("Last-Modified", "Sun, 24 Sep 2023 10:00:00 GMT")
+				return resp, nil
+			}
+			return statusResponse(req, http.StatusNotModified, ""), nil
+		},
+	}}
+}
+
+func TestFetchIntoCache_NotModifiedKeepsCachedHeadlines(t *testing.T) {
+	var requests []*http.Request
+	handler := newRetryTestHandler(conditionalClient(&requests))
+
+	_, err := handler.fetchIntoCache(context.Background())
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	assert.Empty(t, requests[0].Header.Get("If-None-Match"), "the first fetch is unconditional")
+
+	staleAt := time.Now().Add(-time.Hour)
+	handler.multiCache.timestamp = staleAt
+	cached := handler.multiCache.data
+
+	require.NoError(t, handler.refreshHeadlines(context.Background()))
+	require.Len(t, requests, 2)
+	assert.Equal(t, `"v1"`, requests[1].Header.Get("If-None-Match"))
+	assert.Equal(t, "Sun, 24 Sep 2023 10:00:00 GMT", requests[1].Header.Get("If-Modified-Since"))
+
+	// The cached slice is reused as is, so the 304 body was never parsed.
+	assert.Same(t, &cached[0], &handler.multiCache.data[0])
+	assert.True(t, handler.multiCache.timestamp.After(staleAt))
+	headlines, _ := handler.getCachedHeadlines()
+	assert.Len(t, headlines, 6)
+}
+
+func TestFetchLatestHeadline_SharesTheHeadlinesFetch(t *testing.T) {
+	var requests []*http.Request
+	handler := newRetryTestHandler(conditionalClient(&requests))
+	handler.cfg.RSSMinFetchInterval = 0
+
+	_, err := handler.fetchAndCacheHeadlines(context.Background())
+	require.NoError(t, err)
+	latest, totalCount, err := handler.fetchLatestHeadline(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, requests, 1, "latest is served from the headlines top5 cached")
+	assert.Equal(t, "Headline 1", latest.Title)
+	assert.Equal(t, 6, totalCount)
+
+	handler.multiCache.timestamp = time.Now().Add(-time.Hour)
+	latest, _, err = handler.fetchLatestHeadline(context.Background())
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	assert.Equal(t, `"v1"`, requests[1].Header.Get("If-None-Match"), "latest revalidates the cached feed")
+	assert.Equal(t, "Headline 1", latest.Title)
+}
+
+func TestFetchLatestHeadline_MinFetchInterval(t *testing.T) {
+	var upstreams int32
+	client := &http.Client{Transport: &testutil.MockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&upstreams, 1)
+			return statusResponse(req, http.StatusOK, MockRSSResponse), nil
+		},
+	}}
+	handler := NewRSSHandlerWithClient(client)
+	handler.cfg.RSSMinFetchInterval = 30 * time.Second
+
+	for i := 0; i < 5; i++ {
+		handler.ResetCache()
+		_, _, err := handler.fetchLatestHeadline(context.Background())
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&upstreams), "rapid cache resets must not reach the upstream again")
+}
+
+func TestFetchIntoCache_KeepsRawXMLOutOfHeadlines(t *testing.T) {
+	var requests []*http.Request
+	handler := newRetryTestHandler(conditionalClient(&requests))
+
+	headlines, err := handler.fetchIntoCache(context.Background())
+	require.NoError(t, err)
+	require.Len(t, headlines, 6)
+	for _, headline := range headlines {
+		assert.Empty(t, headline.Raw, "cached headlines must not carry the item XML")
+	}
+	require.Len(t, handler.multiCache.rawItems, 6)
+	assert.Contains(t, handler.multiCache.rawItems["https://www.spiegel.de/1"], "Headline 1")
+
+	// An unchanged feed keeps the raw XML of the cached headlines.
+	handler.multiCache.timestamp = time.Now().Add(-time.Hour)
+	require.NoError(t, handler.refreshHeadlines(context.Background()))
+	assert.Len(t, handler.multiCache.rawItems, 6)
+}
+
+func TestFetchIntoCache_UnconditionalAfterReset(t *testing.T) {
+	var requests []*http.Request
+	handler := newRetryTestHandler(conditionalClient(&requests))
+
+	_, err := handler.fetchIntoCache(context.Background())
+	require.NoError(t, err)
+	handler.ResetCache()
+
+	// Without cached headlines the validators are not sent, so a 304 is an upstream error.
+	_, err = handler.fetchIntoCache(context.Background())
+	assert.ErrorIs(t, err, ErrUpstreamStatus)
+	require.Len(t, requests, 2)
+	assert.Empty(t, requests[1].Header.Get("If-None-Match"))
+	assert.Empty(t, requests[1].Header.Get("If-Modified-Since"))
+}
+
+func TestHeadlinesForSource_NotModifiedKeepsCachedHeadlines(t *testing.T) {
+	var requests []*http.Request
+	handler := newRetryTestHandler(conditionalClient(&requests))
+	source := feedSource{SourceInfo: SourceInfo{Key: "heise", Name: "heise online"}, URL: "http://heise.test/rss"}
+
+	_, err := handler.headlinesForSource(context.Background(), source)
+	require.NoError(t, err)
+	handler.sourceCache["heise"].timestamp = time.Now().Add(-time.Hour)
+	cached := handler.sourceCache["heise"].data
+
+	headlines, err := handler.headlinesForSource(context.Background(), source)
+	require.NoError(t, err)
+	assert.Len(t, headlines, 6)
+	require.Len(t, requests, 2)
+	assert.Equal(t, `"v1"`, requests[1].Header.Get("If-None-Match"))
+	assert.Same(t, &cached[0], &handler.sourceCache["heise"].data[0])
+	assert.WithinDuration(t, time.Now(), handler.sourceCache["heise"].timestamp, time.Minute)
+}
+
+// hostClient answers each host with its own response; unknown hosts fail.
+func hostClient(requestedHosts *[]string, responses map[string]func(req *http.Request) (*http.Response, error)) *http.Client {
+	return &http.Client{Transport: &testutil.MockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			*requestedHosts = append(*requestedHosts, req.URL.Host)
+			if respond, ok := responses[req.URL.Host]; ok {
+				return respond(req)
+			}
+			return nil, errors.New("connection refused")
+		},
+	}}
+}
+
+func TestFetchRSSFeed_FallsBackToSecondaryURL(t *testing.T) {
+	var hosts []string
+	handler := newRetryTestHandler(hostClient(&hosts, map[string]func(req *http.Request) (*http.Response, error){
+		"secondary.test": func(req *http.Request) (*http.Response, error) {
+			return statusResponse(req, http.StatusOK, MockRSSResponseFewItems), nil
+		},
+	}))
+	handler.cfg.SpiegelRSSURL = "http://primary.test/rss"
+	handler.cfg.SpiegelRSSFallbackURLs = []string{"http://secondary.test/rss", "http://tertiary.test/rss"}
+
+	headlines, err := handler.fetchAndCacheHeadlines(context.Background())
+	require.NoError(t, err)
+	require.Len(t, headlines, 2)
+	assert.Equal(t, "https://www.spiegel.de/1", headlines[0].Link)
+
+	// The primary is retried before falling back; later fallbacks are not contacted.
+	assert.Equal(t, []string{"primary.test", "primary.test", "primary.test", "secondary.test"}, hosts)
+}
+
+func TestFetchRSSFeed_AllFeedURLsFailReturnsLastError(t *testing.T) {
+	var hosts []string
+	handler := newRetryTestHandler(hostClient(&hosts, map[string]func(req *http.Request) (*http.Response, error){
+		"primary.test": func(req *http.Request) (*http.Response, error) {
+			return statusResponse(req, http.StatusInternalServerError, ""), nil
+		},
+		"secondary.test": func(req *http.Request) (*http.Response, error) {
+			return statusResponse(req, http.StatusNotFound, ""), nil
+		},
+	}))
+	handler.cfg.SpiegelRSSURL = "http://primary.test/rss"
+	handler.cfg.SpiegelRSSFallbackURLs = []string{"http://secondary.test/rss"}
+
+	_, err := handler.fetchRSSFeed(context.Background())
+	assert.ErrorIs(t, err, ErrUpstreamStatus)
+	assert.EqualError(t, err, "upstream returned an unexpected status: status code 404")
+	assert.Equal(t, 1, handler.breaker.failures, "one fetch across all feed URLs counts as one failure")
+}
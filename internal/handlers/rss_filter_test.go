@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 
@@ -243,4 +245,28 @@ func TestRSSHandler_validateFilter(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestRSSHandler_GetTop5_RawXML(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+
+	w := doTop5Request(handler, url.Values{"limit": {"3"}, "raw": {"true"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response HeadlinesResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Headlines, 3)
+
+	sampled := response.Headlines[1]
+	var item struct {
+		XMLName xml.Name `xml:"item"`
+		Title   string   `xml:"title"`
+		Link    string   `xml:"link"`
+	}
+	assert.NoError(t, xml.Unmarshal([]byte(sampled.Raw), &item))
+	assert.Equal(t, sampled.Title, item.Title)
+	assert.Equal(t, sampled.Link, item.Link)
+
+	// Without the flag the raw XML stays out of the response.
+	w = doTop5Request(handler, url.Values{"limit": {"3"}})
+	assert.NotContains(t, w.Body.String(), `"raw"`)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/f00b455/golang-template/pkg/shared"
+)
+
+// fuzzyCharsPerEdit is how many keyword characters allow one edit in fuzzy mode,
+// so "Politk" (6 characters) may differ from "Politik" by one edit.
+const fuzzyCharsPerEdit = 4
+
+// fuzzyFilterHeadlines keeps headlines whose title contains the keyword or has a
+// word within the keyword's edit threshold (case-insensitive).
+func (h *RSSHandler) fuzzyFilterHeadlines(headlines []shared.RssHeadline, keyword string) []shared.RssHeadline {
+	if keyword == "" {
+		return headlines
+	}
+
+	keyword = strings.ToLower(keyword)
+	threshold := len([]rune(keyword)) / fuzzyCharsPerEdit
+
+	filtered := make([]shared.RssHeadline, 0, len(headlines))
+	for _, headline := range headlines {
+		if fuzzyMatchesTitle(strings.ToLower(headline.Title), keyword, threshold) {
+			filtered = append(filtered, headline)
+		}
+	}
+	return filtered
+}
+
+// fuzzyMatchesTitle reports whether title contains keyword or one of its words
+// is at most threshold edits away from it.
+func fuzzyMatchesTitle(title, keyword string, threshold int) bool {
+	if strings.Contains(title, keyword) {
+		return true
+	}
+
+	words := strings.FieldsFunc(title, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if levenshtein(word, keyword) <= threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// levenshtein returns the number of single-rune insertions, deletions and
+// substitutions needed to turn a into b.
+func levenshtein(a, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(target)]
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/f00b455/golang-template/pkg/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		distance int
+	}{
+		{a: "", b: "", distance: 0},
+		{a: "politik", b: "politik", distance: 0},
+		{a: "politik", b: "politk", distance: 1},
+		{a: "kitten", b: "sitting", distance: 3},
+		{a: "", b: "abc", distance: 3},
+		{a: "grüße", b: "grüsse", distance: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.distance, levenshtein(tt.a, tt.b))
+			assert.Equal(t, tt.distance, levenshtein(tt.b, tt.a))
+		})
+	}
+}
+
+func TestRSSHandler_GetTop5_FuzzyFilter(t *testing.T) {
+	handler := NewRSSHandler()
+	handler.multiCache = &multiCacheEntry{
+		data: []shared.RssHeadline{
+			{Title: "Politik: Neue Regierung gebildet"},
+			{Title: "Sport am Wochenende"},
+			{Title: "Innenpolitik im Überblick"},
+		},
+		timestamp: time.Now(),
+	}
+
+	titles := func(filter, fuzzy string) []string {
+		w := doTop5Request(handler, url.Values{"filter": {filter}, "fuzzy": {fuzzy}})
+		require.Equal(t, http.StatusOK, w.Code)
+		var response HeadlinesResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		result := make([]string, len(response.Headlines))
+		for i, headline := range response.Headlines {
+			result[i] = headline.Title
+		}
+		return result
+	}
+
+	assert.Empty(t, titles("Politk", ""), "exact matching stays the default")
+	assert.Equal(t, []string{"Politik: Neue Regierung gebildet"}, titles("Politk", "true"))
+	assert.Equal(t, []string{"Politik: Neue Regierung gebildet", "Innenpolitik im Überblick"}, titles("politik", "true"))
+	assert.Equal(t, []string{"Sport am Wochenende"}, titles("Spot", "true"))
+	assert.Empty(t, titles("um", "true"), "keywords under four characters must match exactly")
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"html"
+	"strings"
+)
+
+// parseImageURL returns the item's image, preferring <media:thumbnail>, then
+// <media:content>, then an <enclosure> with an image type. Items without any
+// of them yield "".
+func (h *RSSHandler) parseImageURL(itemText string) string {
+	var content, enclosure string
+	for _, match := range h.imageTagRegex.FindAllStringSubmatch(itemText, -1) {
+		attrs := h.parseAttributes(match[2])
+		url := html.UnescapeString(strings.TrimSpace(attrs["url"]))
+		if url == "" {
+			continue
+		}
+
+		switch match[1] {
+		case "media:thumbnail":
+			return url
+		case "media:content":
+			if content == "" && isImageType(attrs["type"], true) {
+				content = url
+			}
+		case "enclosure":
+			if enclosure == "" && isImageType(attrs["type"], false) {
+				enclosure = url
+			}
+		}
+	}
+
+	if content != "" {
+		return content
+	}
+	return enclosure
+}
+
+// parseAttributes returns the attributes of a tag's attribute text by name.
+func (h *RSSHandler) parseAttributes(attrText string) map[string]string {
+	attrs := make(map[string]string)
+	for _, match := range h.attrRegex.FindAllStringSubmatch(attrText, -1) {
+		value := match[2]
+		if value == "" {
+			value = match[3]
+		}
+		attrs[match[1]] = value
+	}
+	return attrs
+}
+
+// isImageType reports whether a MIME type denotes an image. A missing type
+// counts as an image only when allowMissing is set.
+func isImageType(mimeType string, allowMissing bool) bool {
+	if mimeType == "" {
+		return allowMissing
+	}
+	return strings.HasPrefix(strings.ToLower(mimeType), "image/")
+}
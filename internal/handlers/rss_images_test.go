@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRSSHandler_ParseImageURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     string
+		expected string
+	}{
+		{
+			name:     "media thumbnail",
+			tags:     `<media:thumbnail url="https://cdn.spiegel.de/thumb.jpg" width="120"/>`,
+			expected: "https://cdn.spiegel.de/thumb.jpg",
+		},
+		{
+			name:     "media content",
+			tags:     `<media:content url="https://cdn.spiegel.de/content.jpg" medium="image"/>`,
+			expected: "https://cdn.spiegel.de/content.jpg",
+		},
+		{
+			name:     "image enclosure",
+			tags:     `<enclosure url='https://cdn.spiegel.de/enclosure.png' type='image/png' length="1"/>`,
+			expected: "https://cdn.spiegel.de/enclosure.png",
+		},
+		{
+			name:     "non-image enclosure is ignored",
+			tags:     `<enclosure url="https://cdn.spiegel.de/podcast.mp3" type="audio/mpeg"/>`,
+			expected: "",
+		},
+		{
+			name: "thumbnail preferred over content and enclosure",
+			tags: `<enclosure url="https://cdn.spiegel.de/enclosure.jpg" type="image/jpeg"/>
+				<media:content url="https://cdn.spiegel.de/content.jpg"/>
+				<media:thumbnail url="https://cdn.spiegel.de/thumb.jpg"/>`,
+			expected: "https://cdn.spiegel.de/thumb.jpg",
+		},
+		{
+			name: "content preferred over enclosure",
+			tags: `<enclosure url="https://cdn.spiegel.de/enclosure.jpg" type="image/jpeg"/>
+				<media:content url="https://cdn.spiegel.de/content.jpg" type="image/jpeg"></media:content>`,
+			expected: "https://cdn.spiegel.de/content.jpg",
+		},
+		{
+			name:     "escaped ampersands are decoded",
+			tags:     `<media:thumbnail url="https://cdn.spiegel.de/img?w=120&amp;h=80"/>`,
+			expected: "https://cdn.spiegel.de/img?w=120&h=80",
+		},
+		{
+			name:     "no image",
+			tags:     "",
+			expected: "",
+		},
+	}
+
+	handler := NewRSSHandler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed := `<rss xmlns:media="http://search.yahoo.com/mrss/"><channel><title>SPIEGEL ONLINE</title><item>
+				<title>Headline</title>
+				<link>https://www.spiegel.de/1</link>
+				` + tt.tags + `
+			</item></channel></rss>`
+
+			headlines := handler.parseMultipleRSSItems(feed, 10)
+			require.Len(t, headlines, 1)
+			assert.Equal(t, tt.expected, headlines[0].ImageURL)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"errors"
+	"regexp"
+)
+
+// callbackPattern matches the JavaScript identifiers accepted as JSONP callback,
+// so a callback can never inject script into the response.
+var callbackPattern = regexp.MustCompile(`^[a-zA-Z_$][\w$]*$`)
+
+// errInvalidCallback rejects a callback that is not a plain JavaScript identifier.
+var errInvalidCallback = errors.New("invalid callback parameter: must be a JavaScript identifier")
+
+// validateCallback checks the JSONP callback query parameter; an empty callback
+// means plain JSON.
+func validateCallback(callback string) error {
+	if callback == "" || callbackPattern.MatchString(callback) {
+		return nil
+	}
+	return errInvalidCallback
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRSSHandler_GetTop5_JSONPCallback(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+
+	for _, callback := range []string{"handleHeadlines", "$jq_123", "_cb"} {
+		t.Run(callback, func(t *testing.T) {
+			w := doTop5Request(handler, url.Values{"callback": {callback}, "limit": {"2"}})
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "application/javascript; charset=utf-8", w.Header().Get("Content-Type"))
+
+			body := w.Body.String()
+			require.True(t, strings.HasPrefix(body, callback+"("), body)
+			require.True(t, strings.HasSuffix(body, ");"), body)
+
+			var response HeadlinesResponse
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimSuffix(strings.TrimPrefix(body, callback+"("), ");")), &response))
+			assert.Len(t, response.Headlines, 2)
+		})
+	}
+}
+
+func TestRSSHandler_GetTop5_WithoutCallbackIsJSON(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+
+	w := doTop5Request(handler, url.Values{})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+}
+
+func TestRSSHandler_GetTop5_RejectsMaliciousCallback(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponse)
+
+	for _, callback := range []string{"alert(1);cb", "cb</script>", "1cb", "a.b", "cb\n", "a-b"} {
+		t.Run(callback, func(t *testing.T) {
+			w := doTop5Request(handler, url.Values{"callback": {callback}})
+			require.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, CodeBadRequest, response.Code)
+			assert.Contains(t, response.Error, "callback")
+		})
+	}
+}
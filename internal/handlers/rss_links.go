@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/f00b455/golang-template/pkg/shared"
+)
+
+// trackingParams are query parameters stripped from canonical links; utm_* is matched by prefix.
+var trackingParams = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"ref":     true,
+	"ref_src": true,
+}
+
+// canonicalizeLink lowercases the host and removes the fragment and tracking
+// parameters. Links that cannot be parsed are returned unchanged.
+func canonicalizeLink(link string) string {
+	parsed, err := url.Parse(link)
+	if err != nil || parsed.Host == "" {
+		return link
+	}
+
+	parsed.Host = strings.ToLower(parsed.Host)
+	parsed.Fragment = ""
+	parsed.RawFragment = ""
+
+	query := parsed.Query()
+	for key := range query {
+		if trackingParams[strings.ToLower(key)] || strings.HasPrefix(strings.ToLower(key), "utm_") {
+			query.Del(key)
+		}
+	}
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String()
+}
+
+// withCanonicalLinks returns a copy of headlines whose Link is canonicalized,
+// keeping the original under RawLink.
+func withCanonicalLinks(headlines []shared.RssHeadline) []shared.RssHeadline {
+	canonical := make([]shared.RssHeadline, len(headlines))
+	for i, headline := range headlines {
+		headline.RawLink = headline.Link
+		headline.Link = canonicalizeLink(headline.Link)
+		canonical[i] = headline
+	}
+	return canonical
+}
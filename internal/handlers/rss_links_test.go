@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalizeLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		link     string
+		expected string
+	}{
+		{
+			name:     "tracking parameters and fragment",
+			link:     "https://WWW.Spiegel.DE/politik/artikel-123?utm_source=rss&utm_medium=feed&fbclid=abc&page=2#comments",
+			expected: "https://www.spiegel.de/politik/artikel-123?page=2",
+		},
+		{
+			name:     "already canonical",
+			link:     "https://www.spiegel.de/1",
+			expected: "https://www.spiegel.de/1",
+		},
+		{
+			name:     "unparseable link is kept",
+			link:     "not a url",
+			expected: "not a url",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, canonicalizeLink(tt.link))
+		})
+	}
+}
+
+func TestRSSHandler_GetTop5_CanonicalLinks(t *testing.T) {
+	trackingLink := "https://WWW.Spiegel.DE/1?utm_campaign=x&id=7#top"
+	feed := strings.Replace(MockRSSResponse, "https://www.spiegel.de/1", trackingLink, 1)
+	handler := newExportTestHandler(t, feed)
+
+	w := doTop5Request(handler, url.Values{"limit": {"1"}, "canonicalLinks": {"true"}})
+	require.Equal(t, http.StatusOK, w.Code)
+	var response HeadlinesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Headlines, 1)
+	assert.Equal(t, "https://www.spiegel.de/1?id=7", response.Headlines[0].Link)
+	assert.Equal(t, "https://WWW.Spiegel.DE/1?utm_campaign=x&id=7#top", response.Headlines[0].RawLink)
+
+	// Default keeps the raw link and omits rawLink.
+	w = doTop5Request(handler, url.Values{"limit": {"1"}})
+	var defaultResponse HeadlinesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &defaultResponse))
+	assert.Equal(t, trackingLink, defaultResponse.Headlines[0].Link)
+	assert.Empty(t, defaultResponse.Headlines[0].RawLink)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// responseKey identifies a headlines response by the shape of its request.
+type responseKey struct {
+	source   string
+	limit    int
+	filter   string
+	category string
+}
+
+type lruEntry struct {
+	key      responseKey
+	value    HeadlinesResponse
+	storedAt time.Time
+}
+
+// responseLRU is a bounded cache of headlines responses. Once it holds
+// maxEntries responses, adding another evicts the least recently used one;
+// entries older than ttl are treated as missing.
+type responseLRU struct {
+	mu         sync.Mutex
+	maxEntries int
+	ttl        time.Duration
+	order      *list.List // front is most recently used
+	entries    map[responseKey]*list.Element
+	now        func() time.Time
+}
+
+func newResponseLRU(maxEntries int, ttl time.Duration) *responseLRU {
+	return &responseLRU{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		order:      list.New(),
+		entries:    make(map[responseKey]*list.Element),
+		now:        time.Now,
+	}
+}
+
+// get returns the response stored under key if it has not expired.
+func (l *responseLRU) get(key responseKey) (HeadlinesResponse, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.entries[key]
+	if !ok {
+		return HeadlinesResponse{}, false
+	}
+	entry := elem.Value.(*lruEntry)
+	if l.now().Sub(entry.storedAt) >= l.ttl {
+		l.removeElement(elem)
+		return HeadlinesResponse{}, false
+	}
+	l.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+// add stores value under key, evicting the least recently used entry when full.
+func (l *responseLRU) add(key responseKey, value HeadlinesResponse) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if elem, ok := l.entries[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		entry.value = value
+		entry.storedAt = l.now()
+		l.order.MoveToFront(elem)
+		return
+	}
+
+	l.entries[key] = l.order.PushFront(&lruEntry{key: key, value: value, storedAt: l.now()})
+	if l.order.Len() > l.maxEntries {
+		l.removeElement(l.order.Back())
+	}
+}
+
+// len returns the number of stored entries, including expired ones not yet removed.
+func (l *responseLRU) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.order.Len()
+}
+
+// purge drops all entries.
+func (l *responseLRU) purge() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.order.Init()
+	l.entries = make(map[responseKey]*list.Element)
+}
+
+func (l *responseLRU) removeElement(elem *list.Element) {
+	l.order.Remove(elem)
+	delete(l.entries, elem.Value.(*lruEntry).key)
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/f00b455/golang-template/pkg/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lruResponse(title string) HeadlinesResponse {
+	return HeadlinesResponse{Headlines: []shared.RssHeadline{{Title: title}}, TotalCount: 1}
+}
+
+func TestResponseLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	lru := newResponseLRU(2, time.Minute)
+	first := responseKey{source: defaultSourceKey, limit: 5}
+	second := responseKey{source: defaultSourceKey, limit: 10}
+	third := responseKey{source: defaultSourceKey, limit: 5, filter: "sport"}
+
+	lru.add(first, lruResponse("first"))
+	lru.add(second, lruResponse("second"))
+	// Reading first makes second the least recently used entry
+	_, ok := lru.get(first)
+	require.True(t, ok)
+	lru.add(third, lruResponse("third"))
+
+	assert.Equal(t, 2, lru.len())
+	_, ok = lru.get(second)
+	assert.False(t, ok, "least recently used entry should be evicted")
+	cached, ok := lru.get(first)
+	assert.True(t, ok)
+	assert.Equal(t, "first", cached.Headlines[0].Title)
+	_, ok = lru.get(third)
+	assert.True(t, ok)
+}
+
+func TestResponseLRU_ExpiresAfterTTL(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	lru := newResponseLRU(10, time.Minute)
+	lru.now = func() time.Time { return now }
+	key := responseKey{source: defaultSourceKey, limit: 5}
+
+	lru.add(key, lruResponse("cached"))
+	now = now.Add(59 * time.Second)
+	_, ok := lru.get(key)
+	assert.True(t, ok, "entry should be served within its TTL")
+
+	now = now.Add(time.Second)
+	_, ok = lru.get(key)
+	assert.False(t, ok, "entry should expire once its TTL has passed")
+	assert.Equal(t, 0, lru.len(), "expired entry should be removed")
+}
+
+func TestRSSHandler_GetTop5_ServesRepeatedRequestsFromLRU(t *testing.T) {
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		_, _ = w.Write([]byte(MockRSSResponse))
+	}))
+	defer server.Close()
+
+	handler := NewRSSHandler()
+	handler.cfg.SpiegelRSSURL = server.URL
+	handler.ResetCache()
+
+	params := url.Values{"limit": {"2"}, "filter": {"Headline"}}
+	first := doTop5Request(handler, params)
+	require.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, 1, handler.responses.len())
+
+	// Requests with shaping parameters bypass the LRU
+	doTop5Request(handler, url.Values{"limit": {"2"}, "raw": {"true"}})
+	assert.Equal(t, 1, handler.responses.len())
+
+	// Swapping the fresh cached headlines proves the second response comes from the LRU
+	handler.mu.Lock()
+	handler.multiCache = &multiCacheEntry{
+		data:      []shared.RssHeadline{{Title: "Headline replaced"}},
+		timestamp: time.Now(),
+	}
+	handler.mu.Unlock()
+
+	second := doTop5Request(handler, url.Values{"limit": {"2"}, "filter": {"HEADLINE"}})
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+}
+
+func TestRSSHandler_GetTop5_ExpiredCacheSkipsLRU(t *testing.T) {
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&fetches, 1) > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(MockRSSResponse))
+	}))
+	defer server.Close()
+
+	handler := NewRSSHandler()
+	handler.cfg.SpiegelRSSURL = server.URL
+	handler.cfg.RSSRetryCount = 0
+	handler.ResetCache()
+
+	params := url.Values{"limit": {"2"}}
+	first := doTop5Request(handler, params)
+	require.Equal(t, http.StatusOK, first.Code)
+	require.Equal(t, 1, handler.responses.len())
+
+	// Expire the headlines while the response is still held by the LRU
+	handler.mu.Lock()
+	handler.multiCache.timestamp = time.Now().Add(-2 * cacheTTL)
+	delete(handler.lastFetches, defaultSourceKey)
+	handler.mu.Unlock()
+
+	second := doTop5Request(handler, params)
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, staleWarning, second.Header().Get("Warning"))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches), "expired cache should hit upstream")
+}
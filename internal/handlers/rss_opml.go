@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxOPMLBytes caps the size of an uploaded OPML document.
+const maxOPMLBytes = 1 << 20
+
+// ErrInvalidOPML is returned for uploads that are not an OPML document with feed outlines.
+var ErrInvalidOPML = errors.New("invalid OPML document")
+
+// ImportSourcesResponse represents the result of an OPML import.
+type ImportSourcesResponse struct {
+	Imported int      `json:"imported" example:"2"`
+	Sources  []string `json:"sources" example:"heise-online,tagesschau"`
+}
+
+type opmlDocument struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    opmlHead `xml:"head"`
+	Body    opmlBody `xml:"body"`
+}
+
+type opmlHead struct {
+	Title       string `xml:"title,omitempty"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+type opmlBody struct {
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// opmlOutline is a feed (when XMLURL is set) or a folder of nested outlines.
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr,omitempty"`
+	Type     string        `xml:"type,attr,omitempty"`
+	XMLURL   string        `xml:"xmlUrl,attr,omitempty"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// ImportSources handles POST /api/sources/import
+// @Summary      Import feed sources from OPML
+// @Description  Registers every <outline xmlUrl="..."> of an OPML document as a source for the /rss/{source} routes. Sources with an existing key are replaced; feeds that are already configured, including the built-in spiegel source, are skipped. The whole import is rejected when a feed host resolves to a loopback, private, link-local or unspecified address, or when it would register more than 50 sources.
+// @Tags         sources
+// @Accept       xml
+// @Produce      json
+// @Security     BearerAuth
+// @Param        opml  body      string  true  "OPML document"
+// @Success      200   {object}  ImportSourcesResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Router       /sources/import [post]
+func (h *RSSHandler) ImportSources(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxOPMLBytes))
+	if err != nil {
+		respondBadRequest(c, fmt.Errorf("%w: %v", ErrInvalidOPML, err))
+		return
+	}
+
+	sources, err := parseOPMLSources(body)
+	if err != nil {
+		respondBadRequest(c, err)
+		return
+	}
+
+	keys := make([]string, 0, len(sources))
+	imported := make([]feedSource, 0, len(sources))
+	for _, source := range sources {
+		if source.Key == spiegelSourceKey || h.isConfiguredFeed(source.URL) {
+			continue
+		}
+		if err := h.checkPublicFeedURL(c.Request.Context(), source.URL); err != nil {
+			respondBadRequest(c, err)
+			return
+		}
+		imported = append(imported, source)
+		keys = append(keys, source.Key)
+	}
+	if err := h.sources.registerAll(imported, maxSources); err != nil {
+		respondBadRequest(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ImportSourcesResponse{Imported: len(keys), Sources: keys})
+}
+
+// ExportSources handles GET /api/sources/export.opml
+// @Summary      Export feed sources as OPML
+// @Description  Downloads the configured sources as an OPML 2.0 document that /sources/import (or another feed reader) accepts
+// @Tags         sources
+// @Produce      xml
+// @Success      200  {string}  string  "OPML document"
+// @Failure      500  {object}  ErrorResponse
+// @Router       /sources/export.opml [get]
+func (h *RSSHandler) ExportSources(c *gin.Context) {
+	body, err := xml.MarshalIndent(h.buildOPMLDocument(time.Now()), "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to generate OPML",
+		})
+		return
+	}
+	body = append([]byte(xml.Header), body...)
+
+	c.Header("Content-Disposition", `attachment; filename="sources.opml"`)
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, "text/x-opml; charset=utf-8", body)
+}
+
+// buildOPMLDocument lists every configured source as a feed outline.
+func (h *RSSHandler) buildOPMLDocument(now time.Time) opmlDocument {
+	sources := h.sources.all()
+	outlines := make([]opmlOutline, len(sources))
+	for i, source := range sources {
+		outlines[i] = opmlOutline{
+			Text:   source.Name,
+			Title:  source.Name,
+			Type:   "rss",
+			XMLURL: h.sourceURL(source),
+		}
+	}
+
+	return opmlDocument{
+		Version: "2.0",
+		Head:    opmlHead{Title: "Feed sources", DateCreated: now.UTC().Format(time.RFC1123Z)},
+		Body:    opmlBody{Outlines: outlines},
+	}
+}
+
+// parseOPMLSources returns the feeds of an OPML document, including those in folders.
+func parseOPMLSources(data []byte) ([]feedSource, error) {
+	var doc opmlDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOPML, err)
+	}
+
+	var sources []feedSource
+	if err := collectOPMLSources(doc.Body.Outlines, &sources); err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("%w: no outline with an xmlUrl", ErrInvalidOPML)
+	}
+	return sources, nil
+}
+
+func collectOPMLSources(outlines []opmlOutline, sources *[]feedSource) error {
+	for _, outline := range outlines {
+		if outline.XMLURL != "" {
+			source, err := outlineSource(outline)
+			if err != nil {
+				return err
+			}
+			*sources = append(*sources, source)
+		}
+		if err := collectOPMLSources(outline.Outlines, sources); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// outlineSource turns a feed outline into a source keyed by the slug of its name.
+func outlineSource(outline opmlOutline) (feedSource, error) {
+	feedURL, err := url.Parse(strings.TrimSpace(outline.XMLURL))
+	if err != nil || (feedURL.Scheme != "http" && feedURL.Scheme != "https") || feedURL.Host == "" {
+		return feedSource{}, fmt.Errorf("%w: xmlUrl %q must be an absolute http(s) URL", ErrInvalidOPML, outline.XMLURL)
+	}
+
+	name := strings.TrimSpace(outline.Text)
+	if name == "" {
+		name = strings.TrimSpace(outline.Title)
+	}
+	if name == "" {
+		name = feedURL.Host
+	}
+
+	key := sourceKey(name)
+	if key == "" {
+		key = sourceKey(feedURL.Host)
+	}
+
+	return feedSource{
+		SourceInfo: SourceInfo{Key: key, Name: name},
+		URL:        feedURL.String(),
+	}, nil
+}
+
+// sourceKey derives a URL-safe key from a source name, e.g. "Heise Online" -> "heise-online".
+func sourceKey(name string) string {
+	var key strings.Builder
+	lastDash := true
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			key.WriteRune(r)
+			lastDash = false
+		} else if !lastDash {
+			key.WriteByte('-')
+			lastDash = true
+		}
+	}
+	return strings.TrimSuffix(key.String(), "-")
+}
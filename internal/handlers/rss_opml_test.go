@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/f00b455/golang-template/internal/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newOPMLRouter registers the import route next to the routes reading sources.
+func newOPMLRouter(handler *RSSHandler) *gin.Engine {
+	router := newSourcesRouter(handler)
+	router.POST("/api/sources/import", handler.ImportSources)
+	router.GET("/api/sources/export.opml", handler.ExportSources)
+	return router
+}
+
+// publicLookup stands in for DNS, resolving every host to a public address.
+func publicLookup(ctx context.Context, host string) ([]net.IP, error) {
+	return []net.IP{net.ParseIP("93.184.216.34")}, nil
+}
+
+// newImportTestHandler returns a handler resolving feed hosts with publicLookup
+// and serving rssContent for every feed it fetches.
+func newImportTestHandler(rssContent string) *RSSHandler {
+	handler := NewRSSHandlerWithClient(&http.Client{Transport: &testutil.MockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			return statusResponse(req, http.StatusOK, rssContent), nil
+		},
+	}})
+	handler.lookupIP = publicLookup
+	return handler
+}
+
+func postOPML(router *gin.Engine, opml string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/api/sources/import", strings.NewReader(opml))
+	req.Header.Set("Content-Type", "text/x-opml")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRSSHandler_ImportSources(t *testing.T) {
+	handler := newImportTestHandler(mockRSSWithCategories)
+	router := newOPMLRouter(handler)
+
+	opml := `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <head><title>My feeds</title></head>
+  <body>
+    <outline text="Heise Online" type="rss" xmlUrl="https://www.heise.de/rss/heise.rdf"/>
+    <outline text="News">
+      <outline title="Tagesschau" type="rss" xmlUrl="https://www.tagesschau.de/xml/rss2/"/>
+    </outline>
+  </body>
+</opml>`
+
+	w := postOPML(router, opml)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response ImportSourcesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Imported)
+	assert.Equal(t, []string{"heise-online", "tagesschau"}, response.Sources)
+	assert.Equal(t, []SourceInfo{
+		{Key: "spiegel", Name: "SPIEGEL ONLINE"},
+		{Key: "heise-online", Name: "Heise Online"},
+		{Key: "tagesschau", Name: "Tagesschau"},
+	}, handler.sources.list())
+
+	// The imported source is served by the generic :source routes
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/rss/heise-online/categories", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"source":"heise-online","categories":["Ausland","Politik","Sport"]}`, w.Body.String())
+}
+
+func TestRSSHandler_ImportSources_RejectsMalformedOPML(t *testing.T) {
+	tests := []struct {
+		name string
+		opml string
+	}{
+		{name: "not XML", opml: "feeds: heise"},
+		{name: "not OPML", opml: `<rss><channel><title>x</title></channel></rss>`},
+		{name: "no feeds", opml: `<opml version="2.0"><body><outline text="Empty"/></body></opml>`},
+		{name: "relative feed URL", opml: `<opml version="2.0"><body><outline text="X" xmlUrl="/feed.xml"/></body></opml>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newImportTestHandler(MockRSSResponse)
+			w := postOPML(newOPMLRouter(handler), tt.opml)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), ErrInvalidOPML.Error())
+			assert.Len(t, handler.sources.list(), 1, "nothing should be registered")
+		})
+	}
+}
+
+func TestRSSHandler_ExportSources_RoundTrips(t *testing.T) {
+	original := newImportTestHandler(MockRSSResponse)
+	imported := postOPML(newOPMLRouter(original), `<opml version="2.0"><body>
+		<outline text="Heise Online" xmlUrl="https://www.heise.de/rss/heise.rdf"/>
+		<outline text="Tagesschau" xmlUrl="https://www.tagesschau.de/xml/rss2/"/>
+	</body></opml>`)
+	require.Equal(t, http.StatusOK, imported.Code)
+
+	w := httptest.NewRecorder()
+	newOPMLRouter(original).ServeHTTP(w, httptest.NewRequest("GET", "/api/sources/export.opml", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/x-opml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="sources.opml"`, w.Header().Get("Content-Disposition"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), `<?xml version="1.0" encoding="UTF-8"?>`))
+	assert.Contains(t, w.Body.String(), `<opml version="2.0">`)
+	assert.Contains(t, w.Body.String(), `xmlUrl="`+original.cfg.SpiegelRSSURL+`"`)
+
+	// Importing the export into a fresh handler yields the same sources
+	restored := newImportTestHandler(MockRSSResponse)
+	reimported := postOPML(newOPMLRouter(restored), w.Body.String())
+	require.Equal(t, http.StatusOK, reimported.Code, reimported.Body.String())
+	assert.Equal(t, original.sources.list(), restored.sources.list())
+	assert.Equal(t, original.sources.all(), restored.sources.all())
+}
+
+func TestRSSHandler_ImportSources_SkipsConfiguredFeeds(t *testing.T) {
+	handler := NewRSSHandler()
+	opml := `<opml version="2.0"><body>
+		<outline text="Der Spiegel" xmlUrl="` + handler.cfg.SpiegelRSSURL + `"/>
+	</body></opml>`
+
+	w := postOPML(newOPMLRouter(handler), opml)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"imported":0,"sources":[]}`, w.Body.String())
+	assert.Len(t, handler.sources.list(), 1)
+}
+
+func TestRSSHandler_ImportSources_RejectsNonPublicHosts(t *testing.T) {
+	resolved := map[string][]net.IP{
+		"metadata.test": {net.ParseIP("169.254.169.254")},
+		"intranet.test": {net.ParseIP("93.184.216.34"), net.ParseIP("10.0.0.7")},
+		"localhost":     {net.ParseIP("127.0.0.1"), net.ParseIP("::1")},
+	}
+	lookup := func(ctx context.Context, host string) ([]net.IP, error) {
+		if ips, ok := resolved[host]; ok {
+			return ips, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	for _, feedURL := range []string{
+		"http://169.254.169.254/latest/meta-data",
+		"http://metadata.test/latest/meta-data",
+		"https://intranet.test/rss",
+		"http://localhost:3002/api/rss/spiegel/top5",
+		"http://[::]/rss",
+		"http://192.168.1.1/rss",
+		"https://unresolvable.test/rss",
+	} {
+		t.Run(feedURL, func(t *testing.T) {
+			handler := NewRSSHandler()
+			handler.lookupIP = lookup
+			opml := `<opml version="2.0"><body>
+				<outline text="Heise" xmlUrl="https://93.184.216.34/rss"/>
+				<outline text="Internal" xmlUrl="` + feedURL + `"/>
+			</body></opml>`
+
+			w := postOPML(newOPMLRouter(handler), opml)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), ErrNonPublicSource.Error())
+			assert.Len(t, handler.sources.list(), 1, "nothing should be registered")
+		})
+	}
+}
+
+func TestRSSHandler_ImportSources_CapsRegisteredSources(t *testing.T) {
+	handler := newImportTestHandler(MockRSSResponse)
+	router := newOPMLRouter(handler)
+
+	outlines := func(from, to int) string {
+		var opml strings.Builder
+		opml.WriteString(`<opml version="2.0"><body>`)
+		for i := from; i < to; i++ {
+			fmt.Fprintf(&opml, `<outline text="Feed %d" xmlUrl="https://feed%d.example.com/rss"/>`, i, i)
+		}
+		opml.WriteString(`</body></opml>`)
+		return opml.String()
+	}
+
+	// The built-in source plus 49 imported ones fill the registry
+	require.Equal(t, http.StatusOK, postOPML(router, outlines(0, maxSources-1)).Code)
+	assert.Len(t, handler.sources.list(), maxSources)
+
+	// Replacing existing keys is still allowed, adding another one is not
+	assert.Equal(t, http.StatusOK, postOPML(router, outlines(0, 3)).Code)
+	w := postOPML(router, outlines(maxSources-2, maxSources))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), ErrTooManySources.Error())
+	assert.Len(t, handler.sources.list(), maxSources)
+}
+
+func TestRSSHandler_ImportedSourceWithoutChannelTitle(t *testing.T) {
+	untitledFeed := `<rss version="2.0"><channel>
+		<item><title>Neue CPU</title><link>https://example.com/1</link></item>
+	</channel></rss>`
+	handler := newImportTestHandler(untitledFeed)
+	router := newOPMLRouter(handler)
+	require.Equal(t, http.StatusOK, postOPML(router, `<opml version="2.0"><body>
+		<outline text="Heise Online" xmlUrl="https://www.heise.de/rss/heise.rdf"/>
+	</body></opml>`).Code)
+
+	source, ok := handler.sources.find("heise-online")
+	require.True(t, ok)
+	headlines, err := handler.headlinesForSource(context.Background(), source)
+	require.NoError(t, err)
+	require.Len(t, headlines, 1)
+	assert.Equal(t, "heise-online", headlines[0].Source)
+}
+
+func TestSourceKey(t *testing.T) {
+	assert.Equal(t, "heise-online", sourceKey("Heise Online"))
+	assert.Equal(t, "faz-net", sourceKey("  FAZ.NET  "))
+	assert.Equal(t, "süddeutsche", sourceKey("Süddeutsche"))
+}
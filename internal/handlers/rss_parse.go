@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/f00b455/golang-template/pkg/shared"
+)
+
+func (h *RSSHandler) parseRSSItem(itemText, source string) (*shared.RssHeadline, error) {
+	// Use pre-compiled regex patterns for better performance
+	titleMatches := h.titleRegex.FindStringSubmatch(itemText)
+	linkMatches := h.linkRegex.FindStringSubmatch(itemText)
+
+	if len(titleMatches) < 2 || len(linkMatches) < 2 {
+		return nil, fmt.Errorf("required RSS fields not found")
+	}
+
+	title := h.cleanTitle(h.cleanCDATA(titleMatches[1]))
+	link := h.cleanCDATA(linkMatches[1])
+
+	publishedAt := time.Now().Format(time.RFC3339)
+	if pubDateMatches := h.pubDateRegex.FindStringSubmatch(itemText); len(pubDateMatches) > 1 {
+		if parsed, ok := parsePubDate(pubDateMatches[1]); ok {
+			publishedAt = parsed.Format(time.RFC3339)
+		}
+	}
+
+	var description string
+	if descMatches := h.descRegex.FindStringSubmatch(itemText); len(descMatches) > 1 {
+		description = strings.TrimSpace(h.cleanCDATA(descMatches[1]))
+	}
+
+	return &shared.RssHeadline{
+		Title:       title,
+		Link:        link,
+		PublishedAt: publishedAt,
+		Source:      source,
+		Description: description,
+		Categories:  h.parseCategories(itemText),
+		ImageURL:    h.parseImageURL(itemText),
+	}, nil
+}
+
+// parseCategories returns the non-empty <category> values of an item in feed order.
+func (h *RSSHandler) parseCategories(itemText string) []string {
+	categories := []string{}
+	for _, match := range h.categoryRegex.FindAllStringSubmatch(itemText, -1) {
+		if category := h.cleanCDATA(match[1]); category != "" {
+			categories = append(categories, category)
+		}
+	}
+	return categories
+}
+
+// pubDateLayouts are the <pubDate> formats accepted, tried in order.
+var pubDateLayouts = []string{time.RFC1123Z, time.RFC1123, time.RFC822Z, time.RFC822, time.RFC3339}
+
+// parsePubDate parses a <pubDate> value in any of pubDateLayouts.
+func parsePubDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range pubDateLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseChannelTitle returns the feed's channel title, falling back to fallback when it is empty.
+func (h *RSSHandler) parseChannelTitle(rssText, fallback string) string {
+	// Only look before the first item so an item title is never mistaken for the channel's
+	channelHeader := rssText
+	if idx := strings.Index(rssText, "<item"); idx >= 0 {
+		channelHeader = rssText[:idx]
+	}
+	if matches := h.titleRegex.FindStringSubmatch(channelHeader); len(matches) > 1 {
+		if title := h.cleanCDATA(matches[1]); title != "" {
+			return title
+		}
+	}
+	return fallback
+}
+
+func (h *RSSHandler) parseMultipleRSSItems(rssText string, limit int) []shared.RssHeadline {
+	return h.parseSourceItems(rssText, limit, defaultSourceKey)
+}
+
+// parseSourceItems parses up to limit items of a feed; their source is the
+// channel title, or fallback when the feed has none.
+func (h *RSSHandler) parseSourceItems(rssText string, limit int, fallback string) []shared.RssHeadline {
+	matches := h.extractRSSItems(rssText, limit)
+	return h.processRSSMatches(matches, limit, h.parseChannelTitle(rssText, fallback), nil)
+}
+
+// parseItemsWithRaw is parseMultipleRSSItems that also returns the original
+// <item> XML of each headline keyed by its link, for raw=true requests.
+func (h *RSSHandler) parseItemsWithRaw(rssText string, limit int) ([]shared.RssHeadline, map[string]string) {
+	rawItems := make(map[string]string)
+	matches := h.extractRSSItems(rssText, limit)
+	headlines := h.processRSSMatches(matches, limit, h.parseChannelTitle(rssText, defaultSourceKey), rawItems)
+	return headlines, rawItems
+}
+
+// extractRSSItems finds RSS item matches in the text
+func (h *RSSHandler) extractRSSItems(rssText string, limit int) [][]string {
+	// Use pre-compiled regex for better performance
+	maxMatches := limit + (limit / 5) // Add 20% buffer for invalid items
+	return h.itemRegex.FindAllStringSubmatch(rssText, maxMatches)
+}
+
+// processRSSMatches converts regex matches to RssHeadline objects. When rawItems
+// is not nil the item XML of each headline is added to it under the headline's
+// link; items sharing a link keep the first one.
+func (h *RSSHandler) processRSSMatches(matches [][]string, limit int, source string, rawItems map[string]string) []shared.RssHeadline {
+	// Pre-allocate with estimated capacity
+	estimatedCapacity := limit
+	if len(matches) < limit {
+		estimatedCapacity = len(matches)
+	}
+	headlines := make([]shared.RssHeadline, 0, estimatedCapacity)
+
+	for i := 0; i < len(matches) && len(headlines) < limit; i++ {
+		if len(matches[i]) < 2 {
+			continue
+		}
+
+		if headline := h.parseItemSafe(matches[i][1], source); headline != nil {
+			if _, seen := rawItems[headline.Link]; rawItems != nil && !seen {
+				rawItems[headline.Link] = matches[i][0]
+			}
+			headlines = append(headlines, *headline)
+		}
+	}
+
+	return headlines
+}
+
+// parseItemSafe safely parses an RSS item, returning nil on error
+func (h *RSSHandler) parseItemSafe(itemText, source string) *shared.RssHeadline {
+	headline, err := h.parseRSSItem(itemText, source)
+	if err != nil {
+		return nil
+	}
+	return headline
+}
+
+func (h *RSSHandler) cleanCDATA(text string) string {
+	text = strings.ReplaceAll(text, "<![CDATA[", "")
+	text = strings.ReplaceAll(text, "]]>", "")
+	return strings.TrimSpace(text)
+}
+
+// cleanTitle turns a feed title into plain text: it strips HTML tags, decodes
+// entities such as &amp; and &#8217;, then strips the tags that were only
+// entity-encoded. Text like "a < b" or "<live>" is kept, as only htmlTagRegex
+// markup is removed.
+func (h *RSSHandler) cleanTitle(title string) string {
+	title = h.htmlTagRegex.ReplaceAllString(title, "")
+	title = html.UnescapeString(title)
+	title = h.htmlTagRegex.ReplaceAllString(title, "")
+	return strings.TrimSpace(title)
+}
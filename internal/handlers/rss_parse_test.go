@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRSSHandler_ParsePubDateLayouts(t *testing.T) {
+	tests := []struct {
+		name     string
+		pubDate  string
+		expected string
+	}{
+		{name: "RFC1123Z", pubDate: "Mon, 02 Jan 2006 15:04:05 +0100", expected: "2006-01-02T15:04:05+01:00"},
+		{name: "RFC1123 with GMT", pubDate: "Mon, 02 Jan 2006 15:04:05 GMT", expected: "2006-01-02T15:04:05Z"},
+		{name: "RFC822Z", pubDate: "02 Jan 06 15:04 -0700", expected: "2006-01-02T15:04:00-07:00"},
+		{name: "RFC822", pubDate: "02 Jan 06 15:04 UTC", expected: "2006-01-02T15:04:00Z"},
+		{name: "RFC3339", pubDate: "2006-01-02T15:04:05+02:00", expected: "2006-01-02T15:04:05+02:00"},
+		{name: "surrounding whitespace", pubDate: " Mon, 02 Jan 2006 15:04:05 +0000 ", expected: "2006-01-02T15:04:05Z"},
+	}
+
+	handler := NewRSSHandler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headline, err := handler.parseRSSItem(`<title>Headline</title><link>https://www.spiegel.de/1</link><pubDate>`+tt.pubDate+`</pubDate>`, "SPIEGEL")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, headline.PublishedAt)
+		})
+	}
+}
+
+func TestRSSHandler_ParsePubDate_UnknownLayoutFallsBackToNow(t *testing.T) {
+	handler := NewRSSHandler()
+
+	before := time.Now().Add(-time.Second)
+	headline, err := handler.parseRSSItem(`<title>Headline</title><link>https://www.spiegel.de/1</link><pubDate>yesterday</pubDate>`, "SPIEGEL")
+	require.NoError(t, err)
+
+	published, err := time.Parse(time.RFC3339, headline.PublishedAt)
+	require.NoError(t, err)
+	assert.False(t, published.Before(before), "unparseable dates fall back to the current time")
+}
+
+func TestRSSHandler_CleanTitle(t *testing.T) {
+	tests := []struct {
+		name     string
+		title    string
+		expected string
+	}{
+		{name: "named entity", title: "Tom &amp; Jerry", expected: "Tom & Jerry"},
+		{name: "numeric entity", title: "Merz&#8217; Rede", expected: "Merz’ Rede"},
+		{name: "embedded tag", title: "<![CDATA[Das <i>Spiegel</i>-Gespräch]]>", expected: "Das Spiegel-Gespräch"},
+		{name: "entity-encoded tag", title: "&lt;b&gt;Eilmeldung&lt;/b&gt;: Streik", expected: "Eilmeldung: Streik"},
+		{name: "unescaped ampersand and comparison", title: "<![CDATA[AT&T: 3 < 5]]>", expected: "AT&T: 3 < 5"},
+		{name: "escaped comparison", title: "Inflation &lt; 2 Prozent", expected: "Inflation < 2 Prozent"},
+		{name: "non-HTML angle brackets", title: "<![CDATA[Bund & Länder <live>]]>", expected: "Bund & Länder <live>"},
+	}
+
+	handler := NewRSSHandler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headline, err := handler.parseRSSItem(`<title>`+tt.title+`</title><link>https://www.spiegel.de/1</link>`, "SPIEGEL")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, headline.Title)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errProxyURL rejects attempts to make the proxy fetch an address instead of a configured source.
+var errProxyURL = errors.New("only configured sources can be proxied: pass source=<key> (see /rss/sources), not a URL")
+
+// ProxyFeed handles GET /api/proxy
+// @Summary      Get the headlines of any configured source
+// @Description  Returns the parsed headlines of a configured feed source in feed order. Only sources listed by /rss/sources can be fetched; URLs are rejected.
+// @Tags         rss
+// @Accept       json
+// @Produce      json
+// @Param        source   query     string  true   "Feed source key (see /rss/sources)"
+// @Param        limit    query     int     false  "Number of headlines (1-200)" minimum(1) maximum(200) default(200)
+// @Param        strict   query     bool    false  "Reject repeated query parameters and out-of-range limits with 400"
+// @Success      200      {object}  HeadlinesResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      503      {object}  ErrorResponse
+// @Failure      504      {object}  ErrorResponse
+// @Router       /proxy [get]
+func (h *RSSHandler) ProxyFeed(c *gin.Context) {
+	if err := checkDuplicateParams(c); err != nil {
+		respondBadRequest(c, err)
+		return
+	}
+
+	key := c.Query("source")
+	if _, hasURL := c.GetQuery("url"); hasURL || strings.ContainsAny(key, ":/") {
+		respondBadRequest(c, errProxyURL)
+		return
+	}
+	if key == "" {
+		respondBadRequest(c, errors.New("source parameter is required"))
+		return
+	}
+	source, ok := h.sources.find(key)
+	if !ok {
+		respondUnknownSource(c, key)
+		return
+	}
+
+	limit, err := parseCountParam(c, "limit", maxReturnItems)
+	if err != nil {
+		respondBadRequest(c, err)
+		return
+	}
+
+	headlines, err := h.headlinesForSource(c.Request.Context(), source)
+	if err != nil {
+		respondUpstreamError(c, err)
+		return
+	}
+
+	selected := headlines
+	if len(headlines) > limit {
+		selected = headlines[:limit]
+	}
+	c.JSON(http.StatusOK, HeadlinesResponse{Headlines: selected, TotalCount: len(headlines)})
+}
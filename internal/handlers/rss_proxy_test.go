@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func doProxyRequest(handler *RSSHandler, query url.Values) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/proxy", handler.ProxyFeed)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/proxy?"+query.Encode(), nil))
+	return w
+}
+
+func TestRSSHandler_ProxyFeed_ConfiguredSource(t *testing.T) {
+	server := SetupMockServer(MockRSSResponse, http.StatusOK)
+	defer server.Close()
+
+	// NewRSSHandler would refuse the loopback test server, as it does for every imported source
+	handler := NewRSSHandlerWithClient(server.Client())
+	handler.sources.register(feedSource{SourceInfo: SourceInfo{Key: "heise", Name: "heise online"}, URL: server.URL})
+
+	w := doProxyRequest(handler, url.Values{"source": {"heise"}, "limit": {"3"}})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response HeadlinesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Headlines, 3)
+	assert.Equal(t, "Headline 1", response.Headlines[0].Title)
+	assert.Empty(t, response.Headlines[0].Raw)
+	assert.Equal(t, 6, response.TotalCount)
+}
+
+func TestRSSHandler_ProxyFeed_BuiltInSource(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponseFewItems)
+
+	w := doProxyRequest(handler, url.Values{"source": {"spiegel"}})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response HeadlinesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Headlines, 2)
+}
+
+func TestRSSHandler_ProxyFeed_RejectsArbitraryURLs(t *testing.T) {
+	var upstreamCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&upstreamCalls, 1)
+		_, _ = w.Write([]byte(MockRSSResponse))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		query          url.Values
+		expectedStatus int
+	}{
+		{name: "url parameter", query: url.Values{"url": {server.URL}}, expectedStatus: http.StatusBadRequest},
+		{name: "url next to a source", query: url.Values{"source": {"spiegel"}, "url": {server.URL}}, expectedStatus: http.StatusBadRequest},
+		{name: "url as source", query: url.Values{"source": {server.URL}}, expectedStatus: http.StatusBadRequest},
+		{name: "path as source", query: url.Values{"source": {"../admin"}}, expectedStatus: http.StatusBadRequest},
+		{name: "missing source", query: url.Values{}, expectedStatus: http.StatusBadRequest},
+		{name: "unknown source", query: url.Values{"source": {"unknown"}}, expectedStatus: http.StatusNotFound},
+	}
+
+	handler := NewRSSHandler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doProxyRequest(handler, tt.query)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+	assert.Zero(t, atomic.LoadInt32(&upstreamCalls), "the proxy must never fetch a caller-supplied address")
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newSeededRand returns a random source seeded for a single call.
+func newSeededRand() *rand.Rand {
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
+
+// GetRandom handles GET /api/rss/spiegel/random
+// @Summary      Get a random SPIEGEL RSS headline
+// @Description  Picks one headline at random from the cached feed, optionally among those matching the filter
+// @Tags         rss
+// @Accept       json
+// @Produce      json
+// @Param        filter   query     string  false  "Only pick among headlines containing this keyword"
+// @Success      200      {object}  shared.RssHeadline
+// @Failure      400      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      503      {object}  ErrorResponse
+// @Failure      504      {object}  ErrorResponse
+// @Router       /rss/spiegel/random [get]
+func (h *RSSHandler) GetRandom(c *gin.Context) {
+	if err := checkDuplicateParams(c); err != nil {
+		respondBadRequest(c, err)
+		return
+	}
+
+	filter := c.Query("filter")
+	if err := h.validateFilter(filter); err != nil {
+		respondBadRequest(c, err)
+		return
+	}
+
+	headlines, err := h.cachedOrFetchedHeadlines(c.Request.Context())
+	if err != nil {
+		respondUpstreamError(c, err)
+		return
+	}
+
+	candidates := h.filterHeadlines(headlines, filter)
+	if len(candidates) == 0 {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "No headlines match the filter"})
+		return
+	}
+
+	c.JSON(http.StatusOK, candidates[h.newRand().Intn(len(candidates))])
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/f00b455/golang-template/pkg/shared"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func doRandomRequest(handler *RSSHandler, query string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/rss/spiegel/random?"+query, nil)
+
+	handler.GetRandom(c)
+	return w
+}
+
+func TestRSSHandler_GetRandom_FilteredPickMatches(t *testing.T) {
+	handler := newSearchTestHandler()
+
+	for seed := int64(0); seed < 20; seed++ {
+		seed := seed
+		handler.newRand = func() *rand.Rand { return rand.New(rand.NewSource(seed)) }
+
+		w := doRandomRequest(handler, "filter=energiewende")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var headline shared.RssHeadline
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &headline))
+		assert.Contains(t, strings.ToLower(headline.Title), "energiewende", "seed %d", seed)
+	}
+}
+
+func TestRSSHandler_GetRandom_DeterministicWithSeed(t *testing.T) {
+	handler := newSearchTestHandler()
+	handler.newRand = func() *rand.Rand { return rand.New(rand.NewSource(7)) }
+
+	first := doRandomRequest(handler, "")
+	second := doRandomRequest(handler, "")
+	require.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+}
+
+func TestRSSHandler_GetRandom_NoMatch(t *testing.T) {
+	w := doRandomRequest(newSearchTestHandler(), "filter=nothing-matches")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "No headlines match the filter", response.Error)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/f00b455/golang-template/pkg/shared"
+	"github.com/gin-gonic/gin"
+)
+
+// GetRecent handles GET /api/rss/{source}/recent
+// @Summary      Get the most recent RSS headlines
+// @Description  Returns the n newest headlines of the source, sorted by publication date (newest first)
+// @Tags         rss
+// @Accept       json
+// @Produce      json
+// @Param        source   path      string  true   "Feed source key (see /rss/sources)"
+// @Param        n        query     int     false  "Number of headlines (1-200)" minimum(1) maximum(200) default(1)
+// @Param        strict   query     bool    false  "Reject repeated query parameters and out-of-range n with 400"
+// @Success      200      {object}  HeadlinesResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      503      {object}  ErrorResponse
+// @Failure      504      {object}  ErrorResponse
+// @Router       /rss/{source}/recent [get]
+func (h *RSSHandler) GetRecent(c *gin.Context) {
+	source, ok := h.sources.find(c.Param("source"))
+	if !ok {
+		respondUnknownSource(c, c.Param("source"))
+		return
+	}
+
+	if err := checkDuplicateParams(c); err != nil {
+		respondBadRequest(c, err)
+		return
+	}
+	n, err := parseCountParam(c, "n", 1)
+	if err != nil {
+		respondBadRequest(c, err)
+		return
+	}
+
+	headlines, err := h.headlinesForSource(c.Request.Context(), source)
+	if err != nil {
+		respondUpstreamError(c, err)
+		return
+	}
+
+	recent := sortByPublishedDesc(headlines)
+	if len(recent) > n {
+		recent = recent[:n]
+	}
+	c.JSON(http.StatusOK, HeadlinesResponse{Headlines: recent, TotalCount: len(headlines)})
+}
+
+// sortByPublishedDesc sorts headlines newest first in place and returns them.
+// Headlines with an unparseable date keep their feed order after the dated ones.
+func sortByPublishedDesc(headlines []shared.RssHeadline) []shared.RssHeadline {
+	published := make(map[string]time.Time, len(headlines))
+	for _, headline := range headlines {
+		if t, err := time.Parse(time.RFC3339, headline.PublishedAt); err == nil {
+			published[headline.PublishedAt] = t
+		}
+	}
+
+	sort.SliceStable(headlines, func(i, j int) bool {
+		ti, iDated := published[headlines[i].PublishedAt]
+		tj, jDated := published[headlines[j].PublishedAt]
+		if iDated != jDated {
+			return iDated
+		}
+		return ti.After(tj)
+	})
+	return headlines
+}
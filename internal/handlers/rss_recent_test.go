@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mockRSSOutOfOrder = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>SPIEGEL ONLINE</title>
+    <item><title>Tuesday</title><link>https://www.spiegel.de/2</link><pubDate>Tue, 02 Jan 2024 10:00:00 +0000</pubDate></item>
+    <item><title>Thursday</title><link>https://www.spiegel.de/4</link><pubDate>Thu, 04 Jan 2024 10:00:00 +0000</pubDate></item>
+    <item><title>Monday</title><link>https://www.spiegel.de/1</link><pubDate>Mon, 01 Jan 2024 10:00:00 +0000</pubDate></item>
+    <item><title>Wednesday</title><link>https://www.spiegel.de/3</link><pubDate>Wed, 03 Jan 2024 10:00:00 +0000</pubDate></item>
+  </channel>
+</rss>`
+
+func newRecentRouter(handler *RSSHandler) *gin.Engine {
+	router := newSourcesRouter(handler)
+	router.GET("/api/rss/spiegel/latest", handler.GetLatest)
+	router.GET("/api/rss/:source/recent", handler.GetRecent)
+	return router
+}
+
+func TestRSSHandler_GetRecent(t *testing.T) {
+	router := newRecentRouter(newExportTestHandler(t, mockRSSOutOfOrder))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/rss/spiegel/recent?n=3", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response HeadlinesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Headlines, 3)
+	assert.Equal(t, "Thursday", response.Headlines[0].Title)
+	assert.Equal(t, "Wednesday", response.Headlines[1].Title)
+	assert.Equal(t, "Tuesday", response.Headlines[2].Title)
+	assert.Equal(t, 4, response.TotalCount)
+}
+
+func TestRSSHandler_GetRecent_DefaultsToOne(t *testing.T) {
+	router := newRecentRouter(newExportTestHandler(t, mockRSSOutOfOrder))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/rss/spiegel/recent", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response HeadlinesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Headlines, 1)
+	assert.Equal(t, "Thursday", response.Headlines[0].Title)
+}
+
+func TestRSSHandler_GetLatest_StillSingleObject(t *testing.T) {
+	router := newRecentRouter(newExportTestHandler(t, mockRSSOutOfOrder))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/rss/spiegel/latest", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.NotContains(t, response, "headlines")
+	assert.Equal(t, "Tuesday", response["title"], "latest keeps returning the feed's first item")
+}
+
+func TestRSSHandler_GetRecent_Errors(t *testing.T) {
+	router := newRecentRouter(newExportTestHandler(t, mockRSSOutOfOrder))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/rss/spiegel/recent?n=0&strict=true", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "n must be between 1 and 200")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/rss/heise/recent", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// StartBackgroundRefresh keeps the headlines cache warm by refetching the feed
+// every refresh interval (the cache TTL) until ctx is cancelled. It returns
+// immediately; the refresh runs in its own goroutine.
+func (h *RSSHandler) StartBackgroundRefresh(ctx context.Context) {
+	h.StartAutoRefresh(ctx, h.refreshInterval)
+}
+
+// StartAutoRefresh refetches the feed every interval until ctx is cancelled,
+// so the cache stays warm without user traffic. An interval of zero or less
+// disables the refresh.
+func (h *RSSHandler) StartAutoRefresh(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go h.refreshLoop(ctx, interval)
+}
+
+// refreshLoop refetches the feed on every tick until ctx is done.
+func (h *RSSHandler) refreshLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.refreshHeadlines(ctx); err != nil {
+				log.Printf("Background feed refresh failed: %v", err)
+			}
+		}
+	}
+}
+
+// warmCache fills the headlines cache once; a failure only means the first
+// request fetches the feed itself.
+func (h *RSSHandler) warmCache() {
+	if _, err := h.fetchAndCacheHeadlines(context.Background()); err != nil {
+		log.Printf("Warming the feed cache failed: %v", err)
+	}
+}
+
+// refreshHeadlines refetches the feed into the cache even while the cache is
+// still fresh. It shares fetchGroup with on-demand fetches, so a refresh and a
+// cache miss never fetch the feed at the same time.
+func (h *RSSHandler) refreshHeadlines(ctx context.Context) error {
+	_, err := h.sharedFetch(ctx, defaultSourceKey, func() (interface{}, error) {
+		return h.fetchIntoCache(ctx)
+	})
+	return err
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/f00b455/golang-template/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRSSHandler_StartBackgroundRefresh_PollsFeed(t *testing.T) {
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		_, _ = w.Write([]byte(MockRSSResponse))
+	}))
+	defer server.Close()
+
+	handler := NewRSSHandler()
+	handler.cfg.SpiegelRSSURL = server.URL
+	handler.refreshInterval = 10 * time.Millisecond
+	handler.ResetCache()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	handler.StartBackgroundRefresh(ctx)
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&fetches) >= 3
+	}, 2*time.Second, 5*time.Millisecond, "feed should be refetched on every tick")
+
+	headlines, _ := handler.getCachedHeadlines()
+	assert.NotEmpty(t, headlines, "refresh should fill the cache")
+
+	// No more polls once the context is cancelled
+	cancel()
+	time.Sleep(30 * time.Millisecond)
+	stopped := atomic.LoadInt32(&fetches)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, stopped, atomic.LoadInt32(&fetches))
+}
+
+func TestNewRSSHandler_RefreshesEveryCacheTTL(t *testing.T) {
+	assert.Equal(t, cacheTTL, NewRSSHandler().refreshInterval)
+	assert.Equal(t, cacheTTL, NewRSSHandlerWithClient(http.DefaultClient).refreshInterval)
+}
+
+func TestNewRSSHandler_WithWarmCache(t *testing.T) {
+	server := SetupMockServer(MockRSSResponse, http.StatusOK)
+	defer server.Close()
+	t.Setenv("SPIEGEL_RSS_URL", server.URL)
+
+	handler := NewRSSHandler(WithWarmCache())
+
+	require.Eventually(t, func() bool {
+		headlines, _ := handler.getCachedHeadlines()
+		return len(headlines) == 6
+	}, 2*time.Second, 5*time.Millisecond, "cache should be warmed after construction")
+}
+
+func TestNewRSSHandlerWithClient_DoesNotWarmByDefault(t *testing.T) {
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		_, _ = w.Write([]byte(MockRSSResponse))
+	}))
+	defer server.Close()
+	t.Setenv("SPIEGEL_RSS_URL", server.URL)
+
+	handler := NewRSSHandlerWithClient(server.Client())
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&fetches))
+	headlines, _ := handler.getCachedHeadlines()
+	assert.Nil(t, headlines)
+}
+
+func TestRSSHandler_StartAutoRefresh(t *testing.T) {
+	var fetches int32
+	handler := NewRSSHandlerWithClient(&http.Client{Transport: &testutil.MockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&fetches, 1)
+			return statusResponse(req, http.StatusOK, MockRSSResponse), nil
+		},
+	}})
+	handler.cfg.SpiegelRSSURL = "http://feed.test/rss"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	handler.StartAutoRefresh(ctx, 10*time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&fetches) >= 3
+	}, 2*time.Second, 5*time.Millisecond, "upstream should be polled on every tick")
+}
+
+func TestRSSHandler_StartAutoRefresh_NonPositiveDisables(t *testing.T) {
+	var fetches int32
+	handler := NewRSSHandlerWithClient(&http.Client{Transport: &testutil.MockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&fetches, 1)
+			return statusResponse(req, http.StatusOK, MockRSSResponse), nil
+		},
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// None of these may reach time.NewTicker, which panics on intervals <= 0
+	handler.StartAutoRefresh(ctx, 0)
+	handler.StartAutoRefresh(ctx, -time.Second)
+	handler.refreshInterval = 0
+	handler.StartBackgroundRefresh(ctx)
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&fetches))
+}
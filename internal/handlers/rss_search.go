@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/f00b455/golang-template/pkg/shared"
+	"github.com/gin-gonic/gin"
+)
+
+// Search relevance ranks; lower ranks are listed first.
+const (
+	rankExactTitle = iota
+	rankTitleContains
+	rankDescriptionContains
+	rankNoMatch
+)
+
+// Search handles GET /api/rss/{source}/search
+// @Summary      Search RSS headlines
+// @Description  Case-insensitive search in headline titles and descriptions. Exact title matches come first, then title matches, then description matches.
+// @Tags         rss
+// @Accept       json
+// @Produce      json
+// @Param        source   path      string  true   "Feed source key (see /rss/sources)"
+// @Param        q        query     string  true   "Search term"
+// @Param        limit    query     int     false  "Number of results (1-200)" minimum(1) maximum(200) default(5)
+// @Param        strict   query     bool    false  "Reject repeated query parameters and out-of-range limits with 400"
+// @Success      200      {object}  HeadlinesResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      503      {object}  ErrorResponse
+// @Failure      504      {object}  ErrorResponse
+// @Router       /rss/{source}/search [get]
+func (h *RSSHandler) Search(c *gin.Context) {
+	source, ok := h.sources.find(c.Param("source"))
+	if !ok {
+		respondUnknownSource(c, c.Param("source"))
+		return
+	}
+
+	query, limit, err := h.parseSearchParams(c)
+	if err != nil {
+		respondBadRequest(c, err)
+		return
+	}
+
+	headlines, err := h.headlinesForSource(c.Request.Context(), source)
+	if err != nil {
+		respondUpstreamError(c, err)
+		return
+	}
+
+	matches := rankSearchResults(headlines, query)
+	totalCount := len(matches)
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	c.JSON(http.StatusOK, HeadlinesResponse{
+		Headlines:  matches,
+		TotalCount: totalCount,
+	})
+}
+
+// parseSearchParams validates the q and limit parameters of a search request.
+func (h *RSSHandler) parseSearchParams(c *gin.Context) (string, int, error) {
+	if err := checkDuplicateParams(c); err != nil {
+		return "", 0, err
+	}
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		return "", 0, fmt.Errorf("missing q parameter")
+	}
+	if err := h.validateFilter(query); err != nil {
+		return "", 0, err
+	}
+
+	limit, err := h.parseLimit(c)
+	return query, limit, err
+}
+
+// rankSearchResults returns the headlines matching query, ordered by searchRank
+// and keeping feed order within the same rank.
+func rankSearchResults(headlines []shared.RssHeadline, query string) []shared.RssHeadline {
+	type rankedHeadline struct {
+		headline shared.RssHeadline
+		rank     int
+	}
+
+	query = strings.ToLower(query)
+	candidates := make([]rankedHeadline, 0, len(headlines))
+	for _, headline := range headlines {
+		if rank := searchRank(headline, query); rank != rankNoMatch {
+			candidates = append(candidates, rankedHeadline{headline: headline, rank: rank})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].rank < candidates[j].rank
+	})
+
+	matches := make([]shared.RssHeadline, len(candidates))
+	for i, candidate := range candidates {
+		matches[i] = candidate.headline
+	}
+	return matches
+}
+
+// searchRank rates how well headline matches the lower-cased query.
+func searchRank(headline shared.RssHeadline, query string) int {
+	title := strings.ToLower(headline.Title)
+	switch {
+	case title == query:
+		return rankExactTitle
+	case strings.Contains(title, query):
+		return rankTitleContains
+	case strings.Contains(strings.ToLower(headline.Description), query):
+		return rankDescriptionContains
+	default:
+		return rankNoMatch
+	}
+}
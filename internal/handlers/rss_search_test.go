@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/f00b455/golang-template/pkg/shared"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// doSearchRequest routes a search request so the :source path parameter is set.
+func doSearchRequest(handler *RSSHandler, path string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/rss/:source/search", handler.Search)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	return w
+}
+
+func newSearchTestHandler() *RSSHandler {
+	handler := NewRSSHandler()
+	handler.multiCache = &multiCacheEntry{
+		data: []shared.RssHeadline{
+			{Title: "Wetter am Wochenende", Description: "Die Energiewende bringt Sonne und Wind"},
+			{Title: "Streit um die Energiewende", Description: "Koalition uneins"},
+			{Title: "Sport kompakt", Description: "Ergebnisse vom Samstag"},
+			{Title: "Energiewende", Description: "Ein Überblick"},
+		},
+		timestamp: time.Now(),
+	}
+	return handler
+}
+
+func TestRSSHandler_Search_RanksTitleAboveDescription(t *testing.T) {
+	w := doSearchRequest(newSearchTestHandler(), "/api/rss/spiegel/search?q=energiewende&limit=10")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response HeadlinesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 3, response.TotalCount)
+	require.Len(t, response.Headlines, 3)
+	assert.Equal(t, "Energiewende", response.Headlines[0].Title, "exact title match first")
+	assert.Equal(t, "Streit um die Energiewende", response.Headlines[1].Title, "title match before description match")
+	assert.Equal(t, "Wetter am Wochenende", response.Headlines[2].Title)
+}
+
+func TestRSSHandler_Search_LimitKeepsTotalCount(t *testing.T) {
+	w := doSearchRequest(newSearchTestHandler(), "/api/rss/spiegel/search?q=ENERGIEWENDE&limit=1")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response HeadlinesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 3, response.TotalCount)
+	require.Len(t, response.Headlines, 1)
+	assert.Equal(t, "Energiewende", response.Headlines[0].Title)
+}
+
+func TestRSSHandler_Search_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{name: "missing query", path: "/api/rss/spiegel/search", expectedStatus: http.StatusBadRequest},
+		{name: "blank query", path: "/api/rss/spiegel/search?q=%20", expectedStatus: http.StatusBadRequest},
+		{name: "strict limit", path: "/api/rss/spiegel/search?q=a&limit=500&strict=true", expectedStatus: http.StatusBadRequest},
+		{name: "unknown source", path: "/api/rss/heise/search?q=a", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doSearchRequest(newSearchTestHandler(), tt.path)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestRSSHandler_ParsesItemDescription(t *testing.T) {
+	handler := NewRSSHandler()
+	feed := `<rss><channel><title>T</title><item>
+		<title>Headline</title>
+		<link>https://www.spiegel.de/1</link>
+		<description><![CDATA[ Mehr zum Thema ]]></description>
+	</item></channel></rss>`
+
+	headlines := handler.parseMultipleRSSItems(feed, 10)
+	require.Len(t, headlines, 1)
+	assert.Equal(t, "Mehr zum Thema", headlines[0].Description)
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+)
+
+// maxSources caps the number of registered sources, including the built-in one,
+// so imports cannot grow the registry and the per-source caches without bound.
+const maxSources = 50
+
+// ErrNonPublicSource is returned for imported feeds whose host resolves to an
+// address the API must not fetch from, such as loopback or cloud metadata addresses.
+var ErrNonPublicSource = errors.New("feed host is not a public address")
+
+// ErrTooManySources is returned when an import would exceed maxSources.
+var ErrTooManySources = fmt.Errorf("too many sources (max %d)", maxSources)
+
+// lookupIP resolves host with the system resolver.
+func lookupIP(ctx context.Context, host string) ([]net.IP, error) {
+	return net.DefaultResolver.LookupIP(ctx, "ip", host)
+}
+
+// checkPublicFeedURL resolves the host of feedURL with h.lookupIP and rejects it
+// when any of its addresses is loopback, private, link-local or unspecified.
+func (h *RSSHandler) checkPublicFeedURL(ctx context.Context, feedURL string) error {
+	parsed, err := url.Parse(feedURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNonPublicSource, err)
+	}
+
+	host := parsed.Hostname()
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		if ips, err = h.lookupIP(ctx, host); err != nil {
+			return fmt.Errorf("%w: resolving %q: %v", ErrNonPublicSource, host, err)
+		}
+	}
+	for _, ip := range ips {
+		if !isPublicIP(ip) {
+			return fmt.Errorf("%w: %q resolves to %s", ErrNonPublicSource, host, ip)
+		}
+	}
+	return nil
+}
+
+// isPublicIP reports whether ip is a routable unicast address outside the
+// loopback, private and link-local ranges.
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast()
+}
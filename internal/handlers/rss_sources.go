@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/f00b455/golang-template/pkg/shared"
+	"github.com/gin-gonic/gin"
+)
+
+// spiegelSourceKey is the :source key of the built-in feed configured by SPIEGEL_RSS_URL.
+const spiegelSourceKey = "spiegel"
+
+// SourceInfo describes a feed the API can serve.
+type SourceInfo struct {
+	Key  string `json:"key" example:"spiegel"`
+	Name string `json:"name" example:"SPIEGEL ONLINE"`
+}
+
+// SourcesResponse represents the response for the list of sources.
+type SourcesResponse struct {
+	Sources []SourceInfo `json:"sources"`
+}
+
+// CategoriesResponse represents the distinct categories of a source's feed.
+type CategoriesResponse struct {
+	Source     string   `json:"source" example:"spiegel"`
+	Categories []string `json:"categories"`
+}
+
+// feedSource is a configured feed together with the address it is fetched from.
+type feedSource struct {
+	SourceInfo
+	// URL is the feed address; the built-in source leaves it empty and uses SPIEGEL_RSS_URL.
+	URL string
+}
+
+// sourceRegistry holds the configured feeds in the order they were added.
+type sourceRegistry struct {
+	mu      sync.RWMutex
+	sources []feedSource
+}
+
+func newSourceRegistry() *sourceRegistry {
+	return &sourceRegistry{
+		sources: []feedSource{{SourceInfo: SourceInfo{Key: spiegelSourceKey, Name: "SPIEGEL ONLINE"}}},
+	}
+}
+
+// find looks up a source by its key (case-insensitive).
+func (r *sourceRegistry) find(key string) (feedSource, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, source := range r.sources {
+		if strings.EqualFold(source.Key, key) {
+			return source, true
+		}
+	}
+	return feedSource{}, false
+}
+
+// list returns the public info of all sources.
+func (r *sourceRegistry) list() []SourceInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	infos := make([]SourceInfo, len(r.sources))
+	for i, source := range r.sources {
+		infos[i] = source.SourceInfo
+	}
+	return infos
+}
+
+// all returns a copy of the sources.
+func (r *sourceRegistry) all() []feedSource {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sources := make([]feedSource, len(r.sources))
+	copy(sources, r.sources)
+	return sources
+}
+
+// registerAll adds sources like register, unless the sources with new keys would
+// grow the registry beyond limit; then nothing is registered.
+func (r *sourceRegistry) registerAll(sources []feedSource, limit int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := make(map[string]bool, len(r.sources)+len(sources))
+	for _, source := range r.sources {
+		keys[strings.ToLower(source.Key)] = true
+	}
+	for _, source := range sources {
+		keys[strings.ToLower(source.Key)] = true
+	}
+	if len(keys) > limit {
+		return ErrTooManySources
+	}
+
+	for _, source := range sources {
+		r.registerLocked(source)
+	}
+	return nil
+}
+
+// register adds source, replacing a source with the same key.
+func (r *sourceRegistry) register(source feedSource) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.registerLocked(source)
+}
+
+// registerLocked is register for callers holding r.mu.
+func (r *sourceRegistry) registerLocked(source feedSource) {
+	for i, existing := range r.sources {
+		if strings.EqualFold(existing.Key, source.Key) {
+			r.sources[i] = source
+			return
+		}
+	}
+	r.sources = append(r.sources, source)
+}
+
+// sourceURL returns the address source is fetched from.
+func (h *RSSHandler) sourceURL(source feedSource) string {
+	if source.URL == "" {
+		return h.cfg.SpiegelRSSURL
+	}
+	return source.URL
+}
+
+// isConfiguredFeed reports whether a source is already fetched from feedURL.
+func (h *RSSHandler) isConfiguredFeed(feedURL string) bool {
+	for _, source := range h.sources.all() {
+		if h.sourceURL(source) == feedURL {
+			return true
+		}
+	}
+	return false
+}
+
+// respondUnknownSource writes the 404 returned for a :source that is not configured.
+func respondUnknownSource(c *gin.Context, key string) {
+	c.JSON(http.StatusNotFound, ErrorResponse{Error: fmt.Sprintf("unknown source %q", key)})
+}
+
+// GetSources handles GET /api/rss/sources
+// @Summary      List RSS sources
+// @Description  Lists the configured feed sources with their display names
+// @Tags         rss
+// @Produce      json
+// @Success      200  {object}  SourcesResponse
+// @Router       /rss/sources [get]
+func (h *RSSHandler) GetSources(c *gin.Context) {
+	c.JSON(http.StatusOK, SourcesResponse{Sources: h.sources.list()})
+}
+
+// GetCategories handles GET /api/rss/{source}/categories
+// @Summary      List RSS categories
+// @Description  Lists the distinct <category> values of the source's cached feed, sorted alphabetically
+// @Tags         rss
+// @Produce      json
+// @Param        source   path      string  true  "Feed source key (see /rss/sources)"
+// @Success      200      {object}  CategoriesResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      503      {object}  ErrorResponse
+// @Failure      504      {object}  ErrorResponse
+// @Router       /rss/{source}/categories [get]
+func (h *RSSHandler) GetCategories(c *gin.Context) {
+	source, ok := h.sources.find(c.Param("source"))
+	if !ok {
+		respondUnknownSource(c, c.Param("source"))
+		return
+	}
+
+	headlines, err := h.headlinesForSource(c.Request.Context(), source)
+	if err != nil {
+		respondUpstreamError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, CategoriesResponse{
+		Source:     source.Key,
+		Categories: distinctCategories(headlines),
+	})
+}
+
+// GetSourceTop5 handles GET /api/rss/{source}/top5
+// @Summary      Get top N headlines of any source
+// @Description  Returns the top N headlines of a configured feed source, optionally filtered by keyword. /rss/spiegel/top5 is served by the SPIEGEL route with all of its parameters.
+// @Tags         rss
+// @Accept       json
+// @Produce      json
+// @Param        source   path      string  true   "Feed source key (see /rss/sources)"
+// @Param        limit    query     int     false  "Number of headlines to fetch (1-200)" minimum(1) maximum(200) default(5)
+// @Param        filter   query     string  false  "Filter headlines by keyword"
+// @Param        strict   query     bool    false  "Reject repeated query parameters and out-of-range limits with 400"
+// @Success      200      {object}  HeadlinesResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      503      {object}  ErrorResponse
+// @Failure      504      {object}  ErrorResponse
+// @Router       /rss/{source}/top5 [get]
+func (h *RSSHandler) GetSourceTop5(c *gin.Context) {
+	source, ok := h.sources.find(c.Param("source"))
+	if !ok {
+		respondUnknownSource(c, c.Param("source"))
+		return
+	}
+
+	if err := checkDuplicateParams(c); err != nil {
+		respondBadRequest(c, err)
+		return
+	}
+	limit, err := h.parseLimit(c)
+	if err != nil {
+		respondBadRequest(c, err)
+		return
+	}
+	filter := c.Query("filter")
+	if err := h.validateFilter(filter); err != nil {
+		respondBadRequest(c, err)
+		return
+	}
+
+	headlines, err := h.headlinesForSource(c.Request.Context(), source)
+	if err != nil {
+		respondUpstreamError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, HeadlinesResponse{
+		Headlines:  h.applyFilterAndLimit(headlines, filter, limit),
+		TotalCount: len(headlines),
+	})
+}
+
+// cachedOrFetchedHeadlines returns the cached headlines, fetching them on a cache miss.
+func (h *RSSHandler) cachedOrFetchedHeadlines(ctx context.Context) ([]shared.RssHeadline, error) {
+	if headlines, _ := h.getCachedHeadlines(); headlines != nil {
+		return headlines, nil
+	}
+	return h.fetchAndCacheHeadlines(ctx)
+}
+
+// headlinesForSource returns the cached headlines of source, fetching them on a
+// cache miss. Sources other than the built-in one are cached per key and are
+// fetched without the circuit breaker, which guards SPIEGEL_RSS_URL only.
+func (h *RSSHandler) headlinesForSource(ctx context.Context, source feedSource) ([]shared.RssHeadline, error) {
+	if source.URL == "" {
+		return h.cachedOrFetchedHeadlines(ctx)
+	}
+
+	h.mu.RLock()
+	entry := h.sourceCache[source.Key]
+	h.mu.RUnlock()
+	if entry != nil && time.Since(entry.timestamp) < cacheTTL {
+		return copyHeadlines(entry.data), nil
+	}
+
+	result, err := h.sharedFetch(ctx, "source:"+source.Key, func() (interface{}, error) {
+		if last := h.recentFetch(source.Key); last != nil {
+			h.mu.Lock()
+			h.sourceCache[source.Key] = last
+			h.mu.Unlock()
+			return last.data, nil
+		}
+
+		var validators feedValidators
+		if entry != nil && len(entry.data) > 0 {
+			validators = entry.validators
+		}
+		fetched, err := h.fetchFeedWithRetry(ctx, h.sourceClient, source.URL, validators)
+		if err != nil {
+			return nil, err
+		}
+		if fetched.notModified {
+			h.mu.Lock()
+			h.sourceCache[source.Key] = &multiCacheEntry{data: entry.data, timestamp: time.Now(), validators: entry.validators}
+			h.lastFetches[source.Key] = h.sourceCache[source.Key]
+			h.mu.Unlock()
+			return entry.data, nil
+		}
+
+		headlines := h.parseSourceItems(fetched.body, h.cfg.RSSMaxFetchItems, source.Key)
+		if len(headlines) == 0 {
+			return nil, ErrParse
+		}
+
+		h.mu.Lock()
+		h.sourceCache[source.Key] = &multiCacheEntry{data: headlines, timestamp: time.Now(), validators: fetched.validators}
+		h.lastFetches[source.Key] = h.sourceCache[source.Key]
+		h.mu.Unlock()
+		return headlines, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copyHeadlines(result.([]shared.RssHeadline)), nil
+}
+
+// copyHeadlines returns a copy of headlines so callers never alias a cached slice.
+func copyHeadlines(headlines []shared.RssHeadline) []shared.RssHeadline {
+	copied := make([]shared.RssHeadline, len(headlines))
+	copy(copied, headlines)
+	return copied
+}
+
+// distinctCategories returns the sorted, de-duplicated categories of headlines.
+func distinctCategories(headlines []shared.RssHeadline) []string {
+	seen := make(map[string]bool)
+	categories := []string{}
+	for _, headline := range headlines {
+		for _, category := range headline.Categories {
+			if !seen[category] {
+				seen[category] = true
+				categories = append(categories, category)
+			}
+		}
+	}
+	sort.Strings(categories)
+	return categories
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mockRSSWithCategories = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>SPIEGEL ONLINE</title>
+    <item>
+      <title>Headline 1</title>
+      <link>https://www.spiegel.de/1</link>
+      <category>Politik</category>
+      <category><![CDATA[Ausland]]></category>
+    </item>
+    <item>
+      <title>Headline 2</title>
+      <link>https://www.spiegel.de/2</link>
+      <category domain="https://www.spiegel.de">Politik</category>
+      <category>Sport</category>
+    </item>
+    <item>
+      <title>Headline 3</title>
+      <link>https://www.spiegel.de/3</link>
+    </item>
+  </channel>
+</rss>`
+
+// newSourcesRouter registers the source routes next to the fixed spiegel routes,
+// as cmd/api does, so route conflicts surface in tests.
+func newSourcesRouter(handler *RSSHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/rss/spiegel/top5", handler.GetTop5)
+	router.GET("/api/rss/sources", handler.GetSources)
+	router.GET("/api/rss/:source/top5", handler.GetSourceTop5)
+	router.GET("/api/rss/:source/search", handler.Search)
+	router.GET("/api/rss/:source/categories", handler.GetCategories)
+	router.GET("/api/rss/:source/cache", handler.GetCacheStatus)
+	return router
+}
+
+func TestRSSHandler_GetSources(t *testing.T) {
+	w := httptest.NewRecorder()
+	newSourcesRouter(NewRSSHandler()).ServeHTTP(w, httptest.NewRequest("GET", "/api/rss/sources", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response SourcesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []SourceInfo{{Key: "spiegel", Name: "SPIEGEL ONLINE"}}, response.Sources)
+}
+
+func TestRSSHandler_GetCategories(t *testing.T) {
+	handler := newExportTestHandler(t, mockRSSWithCategories)
+
+	w := httptest.NewRecorder()
+	newSourcesRouter(handler).ServeHTTP(w, httptest.NewRequest("GET", "/api/rss/spiegel/categories", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response CategoriesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "spiegel", response.Source)
+	assert.Equal(t, []string{"Ausland", "Politik", "Sport"}, response.Categories)
+}
+
+func TestRSSHandler_GetCategories_NoCategories(t *testing.T) {
+	handler := newExportTestHandler(t, MockRSSResponseFewItems)
+
+	w := httptest.NewRecorder()
+	newSourcesRouter(handler).ServeHTTP(w, httptest.NewRequest("GET", "/api/rss/spiegel/categories", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"source":"spiegel","categories":[]}`, w.Body.String())
+}
+
+func TestRSSHandler_GetCategories_UnknownSource(t *testing.T) {
+	w := httptest.NewRecorder()
+	newSourcesRouter(NewRSSHandler()).ServeHTTP(w, httptest.NewRequest("GET", "/api/rss/heise/categories", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRSSHandler_GetSourceTop5(t *testing.T) {
+	handler := newImportTestHandler(mockRSSWithCategories)
+	handler.sources.register(feedSource{
+		SourceInfo: SourceInfo{Key: "heise", Name: "heise online"},
+		URL:        "https://www.heise.de/rss/heise.rdf",
+	})
+	router := newSourcesRouter(handler)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/rss/heise/top5?limit=1&filter=Headline", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response HeadlinesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Headlines, 1)
+	assert.Equal(t, "Headline 1", response.Headlines[0].Title)
+	assert.Equal(t, 3, response.TotalCount)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/rss/heise/top5?filter="+strings.Repeat("a", maxFilterLength+1), nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRSSHandler_GetSourceTop5_UnknownSource(t *testing.T) {
+	w := httptest.NewRecorder()
+	newSourcesRouter(NewRSSHandler()).ServeHTTP(w, httptest.NewRequest("GET", "/api/rss/heise/top5", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRSSHandler_ParsesItemCategories(t *testing.T) {
+	handler := NewRSSHandler()
+
+	headlines := handler.parseMultipleRSSItems(mockRSSWithCategories, 10)
+	require.Len(t, headlines, 3)
+	assert.Equal(t, []string{"Politik", "Ausland"}, headlines[0].Categories)
+	assert.Equal(t, []string{"Politik", "Sport"}, headlines[1].Categories)
+	assert.NotNil(t, headlines[2].Categories, "items without categories get an empty slice")
+	assert.Empty(t, headlines[2].Categories)
+
+	encoded, err := json.Marshal(headlines[2])
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"categories":[]`)
+}
+
+func TestRSSHandler_GetTop5_CategoryFilter(t *testing.T) {
+	handler := newExportTestHandler(t, mockRSSWithCategories)
+
+	w := doTop5Request(handler, url.Values{"category": {"politik"}, "limit": {"10"}})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response HeadlinesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Headlines, 2)
+	assert.Equal(t, "Headline 1", response.Headlines[0].Title)
+	assert.Equal(t, "Headline 2", response.Headlines[1].Title)
+
+	// Category matching is exact, not a substring match
+	w = doTop5Request(handler, url.Values{"category": {"Spo"}})
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Empty(t, response.Headlines)
+
+	// Several categories match headlines having any of them
+	w = doTop5Request(handler, url.Values{"category": {"Ausland,sport"}})
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Headlines, 2)
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuth returns a middleware that only lets requests through when they carry
+// "Authorization: Bearer <token>" matching the configured admin token. A header
+// without the Bearer scheme is rejected even if it carries the bare token.
+func AdminAuth(token string) gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		provided, hasBearer := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if token == "" || !hasBearer || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		c.Next()
+	})
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		token          string
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "valid token", token: "secret", authorization: "Bearer secret", expectedStatus: http.StatusOK},
+		{name: "wrong token", token: "secret", authorization: "Bearer nope", expectedStatus: http.StatusUnauthorized},
+		{name: "missing header", token: "secret", authorization: "", expectedStatus: http.StatusUnauthorized},
+		{name: "bare token without Bearer scheme", token: "secret", authorization: "secret", expectedStatus: http.StatusUnauthorized},
+		{name: "other scheme", token: "secret", authorization: "Basic secret", expectedStatus: http.StatusUnauthorized},
+		{name: "no token configured", token: "", authorization: "Bearer ", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/admin", AdminAuth(tt.token), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/admin", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
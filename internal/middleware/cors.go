@@ -1,22 +1,85 @@
 package middleware
 
 import (
+	"net/http"
+	"strings"
+
+	"github.com/f00b455/golang-template/internal/config"
 	"github.com/gin-gonic/gin"
 )
 
-// CORS returns a middleware that handles CORS headers.
+// CORSConfig lists what cross-origin requests may do. Listed origins are echoed
+// and may send credentials. An AllowedOrigins entry of "*" allows every other
+// origin with a literal "*", which browsers only honour for requests without
+// credentials.
+type CORSConfig struct {
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+}
+
+// CORS returns a middleware that handles CORS headers as configured by the
+// CORS_ALLOWED_ORIGINS, CORS_ALLOWED_METHODS and CORS_ALLOWED_HEADERS environment variables.
 func CORS() gin.HandlerFunc {
+	cfg := config.Load()
+	return CORSWithConfig(CORSConfig{
+		AllowedOrigins: cfg.CORSAllowedOrigins,
+		AllowedMethods: cfg.CORSAllowedMethods,
+		AllowedHeaders: cfg.CORSAllowedHeaders,
+	})
+}
+
+// CORSWithConfig returns a middleware that handles CORS headers for the configured
+// origins. Requests from other origins get no CORS headers, and their preflight
+// requests are rejected with 403.
+func CORSWithConfig(cfg CORSConfig) gin.HandlerFunc {
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+
 	return gin.HandlerFunc(func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "http://localhost:3000")
-		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		origin := c.GetHeader("Origin")
+		listed, wildcard := false, false
+		if origin != "" {
+			listed, wildcard = originAllowed(cfg.AllowedOrigins, origin)
+		}
+		allowed := listed || wildcard
+
+		switch {
+		case listed:
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Access-Control-Allow-Credentials", "true")
+		case wildcard:
+			c.Header("Access-Control-Allow-Origin", "*")
+		}
+		if allowed {
+			c.Header("Access-Control-Allow-Headers", headers)
+			c.Header("Access-Control-Allow-Methods", methods)
+		}
+		c.Header("Vary", "Origin")
 
 		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+			if origin != "" && !allowed {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
 
 		c.Next()
 	})
 }
+
+// originAllowed reports whether origin is one of the allowed origins and, if it
+// is not, whether the allowed origins contain the "*" wildcard.
+func originAllowed(allowedOrigins []string, origin string) (listed, wildcard bool) {
+	for _, allowedOrigin := range allowedOrigins {
+		if strings.EqualFold(allowedOrigin, origin) {
+			return true, false
+		}
+		if allowedOrigin == "*" {
+			wildcard = true
+		}
+	}
+	return false, wildcard
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCORSRouter(cfg CORSConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORSWithConfig(cfg))
+	router.GET("/api/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestCORSWithConfig_Origins(t *testing.T) {
+	cfg := CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com", "http://localhost:3000"},
+		AllowedMethods: []string{"GET", "OPTIONS"},
+		AllowedHeaders: []string{"Content-Type"},
+	}
+
+	tests := []struct {
+		name                string
+		allowed             []string
+		origin              string
+		expectedAllow       string
+		expectedCredentials string
+	}{
+		{name: "allowed origin is echoed", allowed: cfg.AllowedOrigins, origin: "https://app.example.com", expectedAllow: "https://app.example.com", expectedCredentials: "true"},
+		{name: "disallowed origin gets no header", allowed: cfg.AllowedOrigins, origin: "https://evil.example.com", expectedAllow: ""},
+		{name: "wildcard allows any origin without credentials", allowed: []string{"*"}, origin: "https://any.example.com", expectedAllow: "*"},
+		{name: "listed origin next to the wildcard keeps credentials", allowed: []string{"*", "https://app.example.com"}, origin: "https://app.example.com", expectedAllow: "https://app.example.com", expectedCredentials: "true"},
+		{name: "no origin", allowed: cfg.AllowedOrigins, origin: "", expectedAllow: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			routerCfg := cfg
+			routerCfg.AllowedOrigins = tt.allowed
+			router := newCORSRouter(routerCfg)
+
+			req := httptest.NewRequest("GET", "/api/test", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expectedAllow, w.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, tt.expectedCredentials, w.Header().Get("Access-Control-Allow-Credentials"))
+			if tt.expectedAllow != "" {
+				assert.Equal(t, "GET, OPTIONS", w.Header().Get("Access-Control-Allow-Methods"))
+				assert.Equal(t, "Content-Type", w.Header().Get("Access-Control-Allow-Headers"))
+			}
+		})
+	}
+}
+
+func TestCORSWithConfig_RejectsDisallowedPreflight(t *testing.T) {
+	router := newCORSRouter(CORSConfig{AllowedOrigins: []string{"http://localhost:3000"}})
+
+	req := httptest.NewRequest("OPTIONS", "/api/test", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORS_ReadsEnvironment(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com")
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS())
+	router.GET("/api/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest("GET", "/api/test", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORS_PreflightShortCircuits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS())
+
+	handlerCalled := false
+	router.GET("/api/rss/spiegel/top5", func(c *gin.Context) {
+		handlerCalled = true
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("OPTIONS", "/api/rss/spiegel/top5", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), "GET")
+	assert.Equal(t, "http://localhost:3000", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.False(t, handlerCalled, "preflight must not reach the route handler")
+}
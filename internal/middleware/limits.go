@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LimitQueryLength returns a middleware that rejects requests whose raw query
+// string is longer than max bytes with 400, before any handler parses it.
+func LimitQueryLength(max int) gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		if len(c.Request.URL.RawQuery) > max {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("query string too long (max %d characters)", max),
+			})
+			return
+		}
+
+		c.Next()
+	})
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitQueryLength(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{name: "no query", query: "", expectedStatus: http.StatusOK},
+		{name: "normal query", query: "limit=5&filter=berlin", expectedStatus: http.StatusOK},
+		{name: "query at the limit", query: "q=" + strings.Repeat("a", 30), expectedStatus: http.StatusOK},
+		{name: "over-long query", query: "q=" + strings.Repeat("a", 31), expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/rss", LimitQueryLength(32), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/rss?"+tt.query, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Contains(t, w.Body.String(), "query string too long (max 32 characters)")
+			}
+		})
+	}
+}
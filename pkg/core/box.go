@@ -0,0 +1,51 @@
+package core
+
+import (
+	"strings"
+
+	"golang.org/x/text/width"
+)
+
+// boxTitle is the heading shown in the top section of a boxed greeting.
+const boxTitle = "Hello CLI"
+
+// FormatBoxedGreeting renders the FooGreet greeting inside a plain text box.
+func FormatBoxedGreeting(config FooConfig, name string) string {
+	return FormatBox(FooGreet(config, name))
+}
+
+// FormatBox renders an already formatted greeting inside a plain text box.
+// All lines have the same terminal display width, so callers can colorize or
+// indent the result without breaking the frame.
+func FormatBox(greeting string) string {
+	greetingWidth := displayWidth(greeting)
+
+	// The box fits the greeting plus padding, and at least the title
+	innerWidth := max(greetingWidth+2, displayWidth(boxTitle))
+	blank := strings.Repeat(" ", innerWidth)
+
+	lines := []string{
+		"╔" + blank + "╗",
+		"║" + strings.Repeat(" ", innerWidth-displayWidth(boxTitle)) + boxTitle + "║",
+		"╠" + blank + "╣",
+		"║ " + greeting + strings.Repeat(" ", innerWidth-greetingWidth-1) + "║",
+		"║" + blank + "║",
+		"╚" + blank + "╝",
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// displayWidth returns the number of terminal columns s occupies, counting
+// East Asian wide and fullwidth runes (CJK, most emoji) as two columns.
+func displayWidth(s string) int {
+	columns := 0
+	for _, r := range s {
+		switch width.LookupRune(r).Kind() {
+		case width.EastAsianWide, width.EastAsianFullwidth:
+			columns += 2
+		default:
+			columns++
+		}
+	}
+	return columns
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatBoxedGreeting_Dimensions(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        FooConfig
+		input         string
+		expectedWidth int
+	}{
+		{
+			name:          "short name",
+			config:        FooConfig{},
+			input:         "Al",
+			expectedWidth: len("Hello, Al!") + 4,
+		},
+		{
+			name:          "long name",
+			config:        FooConfig{Prefix: ">> ", Suffix: " <<"},
+			input:         "Maximilian Alexander",
+			expectedWidth: len(">> Hello, Maximilian Alexander! <<") + 4,
+		},
+		{
+			name:          "umlaut name",
+			config:        FooConfig{},
+			input:         "Müller",
+			expectedWidth: len("Hello, Muller!") + 4,
+		},
+		{
+			name:          "east asian wide name",
+			config:        FooConfig{},
+			input:         "日本",
+			expectedWidth: len("Hello, !") + 4 + 4,
+		},
+		{
+			name:          "emoji decoration",
+			config:        FooConfig{Prefix: "✨ ", Suffix: " ✨"},
+			input:         "Müller",
+			expectedWidth: len("Hello, Muller!") + 6 + 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			box := FormatBoxedGreeting(tt.config, tt.input)
+
+			lines := strings.Split(strings.TrimSuffix(box, "\n"), "\n")
+			require.Len(t, lines, 6)
+			for _, line := range lines {
+				assert.Equal(t, tt.expectedWidth, displayWidth(line), "line %q", line)
+			}
+			assert.Contains(t, lines[3], FooGreet(tt.config, tt.input))
+			assert.True(t, strings.HasPrefix(lines[0], "╔"))
+			assert.True(t, strings.HasSuffix(lines[5], "╝"))
+		})
+	}
+}
+
+func TestFormatBoxedGreeting_Layout(t *testing.T) {
+	box := FormatBoxedGreeting(FooConfig{}, "A")
+
+	expected := "╔           ╗\n" +
+		"║  Hello CLI║\n" +
+		"╠           ╣\n" +
+		"║ Hello, A! ║\n" +
+		"║           ║\n" +
+		"╚           ╝\n"
+	assert.Equal(t, expected, box)
+}
+
+func TestFormatBox_GreetingNarrowerThanTitle(t *testing.T) {
+	tests := []struct {
+		name     string
+		greeting string
+	}{
+		{name: "two letters", greeting: "Hi"},
+		{name: "empty", greeting: ""},
+		{name: "narrower than the title", greeting: "Hi Bob"},
+		{name: "padded greeting as wide as the title", greeting: "Hi Carl"},
+		{name: "short template", greeting: FooGreet(FooConfig{Template: "Hi {{.Name}}"}, "Bo")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			box := FormatBox(tt.greeting)
+
+			lines := strings.Split(strings.TrimSuffix(box, "\n"), "\n")
+			require.Len(t, lines, 6)
+			for _, line := range lines {
+				assert.Equal(t, displayWidth(boxTitle)+2, displayWidth(line), "line %q", line)
+			}
+			assert.Equal(t, "║"+boxTitle+"║", lines[1])
+			assert.True(t, strings.HasPrefix(lines[3], "║ "+tt.greeting))
+		})
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	assert.Equal(t, 6, displayWidth("Müller"))
+	assert.Equal(t, 4, displayWidth("日本"))
+	assert.Equal(t, 2, displayWidth("✨"))
+	assert.Equal(t, 1, displayWidth("║"))
+}
@@ -1,11 +1,36 @@
 package core
 
-import "github.com/f00b455/golang-template/pkg/shared"
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/f00b455/golang-template/pkg/shared"
+)
 
 // FooConfig holds configuration for foo processing.
 type FooConfig struct {
 	Prefix string
 	Suffix string
+	// Lang selects the greeting language ("en", "de", "fr", "es"); unknown codes fall back to English.
+	Lang string
+	// Template is an optional text/template (e.g. "Moin {{.Name}}!") used instead of the
+	// default greeting; it is still wrapped in Prefix and Suffix.
+	Template string
+}
+
+// templateData is the data passed to FooConfig.Template.
+type templateData struct {
+	Name string
+}
+
+// greetingTemplates maps supported language codes to their greeting format.
+// English is served by shared.Greet and therefore not listed.
+var greetingTemplates = map[string]string{
+	"de": "Hallo, %s!",
+	"fr": "Bonjour, %s !",
+	"es": "¡Hola, %s!",
 }
 
 // FooProcess applies prefix and suffix to input string.
@@ -13,10 +38,66 @@ func FooProcess(config FooConfig, input string) string {
 	return config.Prefix + input + config.Suffix
 }
 
-// FooGreet creates a greeting with foo processing.
+// FooGreet creates a greeting in the configured language with foo processing.
+// The name is cleaned with SanitizeName first. A configured Template takes
+// precedence; if it is invalid the default greeting is used.
 func FooGreet(config FooConfig, name string) string {
-	greeting := shared.Greet(name)
-	return FooProcess(config, greeting)
+	name = SanitizeName(name)
+	if config.Template != "" {
+		if greeting, err := FooGreetTemplate(config, name); err == nil {
+			return greeting
+		}
+	}
+	return FooProcess(config, localizedGreet(config.Lang, name))
+}
+
+// FooGreetTemplate greets name using config.Template, falling back to FooGreet
+// when no template is set. It returns an error if the template cannot be parsed or executed.
+func FooGreetTemplate(config FooConfig, name string) (string, error) {
+	if config.Template == "" {
+		return FooGreet(config, name), nil
+	}
+
+	tmpl, err := template.New("greeting").Option("missingkey=error").Parse(config.Template)
+	if err != nil {
+		return "", fmt.Errorf("invalid greeting template: %w", err)
+	}
+
+	var greeting strings.Builder
+	if err := tmpl.Execute(&greeting, templateData{Name: SanitizeName(name)}); err != nil {
+		return "", fmt.Errorf("invalid greeting template: %w", err)
+	}
+	return FooProcess(config, greeting.String()), nil
+}
+
+// FooGreetAt greets name according to the time of day of t ("Good morning" from 6:00,
+// "Good afternoon" from 12:00, "Good evening" from 18:00, "Good night" from 22:00),
+// with the same prefix and suffix as FooGreet.
+func FooGreetAt(config FooConfig, name string, t time.Time) string {
+	return FooProcess(config, fmt.Sprintf("%s, %s!", timeOfDaySalutation(t.Hour()), SanitizeName(name)))
+}
+
+// timeOfDaySalutation returns the salutation for an hour of the day (0-23).
+func timeOfDaySalutation(hour int) string {
+	switch {
+	case hour >= 6 && hour < 12:
+		return "Good morning"
+	case hour >= 12 && hour < 18:
+		return "Good afternoon"
+	case hour >= 18 && hour < 22:
+		return "Good evening"
+	default:
+		return "Good night"
+	}
+}
+
+// localizedGreet greets name in lang, using shared.Greet for English and unknown languages.
+func localizedGreet(lang, name string) string {
+	format, ok := greetingTemplates[strings.ToLower(lang)]
+	if !ok {
+		return shared.Greet(name)
+	}
+	return fmt.Sprintf(format, name)
 }
 
 // FooProcessor holds configuration and provides processing methods.
@@ -2,6 +2,7 @@ package core
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -55,9 +56,9 @@ func TestFooGreet(t *testing.T) {
 			expected: "✨Hello, Alice!✨",
 		},
 		{
-			name:     "empty name",
+			name:     "empty name falls back to World",
 			input:    "",
-			expected: "✨Error: Name cannot be empty✨",
+			expected: "✨Hello, World!✨",
 		},
 	}
 
@@ -69,6 +70,98 @@ func TestFooGreet(t *testing.T) {
 	}
 }
 
+func TestFooGreet_Languages(t *testing.T) {
+	tests := []struct {
+		name     string
+		lang     string
+		expected string
+	}{
+		{name: "default", lang: "", expected: "Hello, Alice!"},
+		{name: "english", lang: "en", expected: "Hello, Alice!"},
+		{name: "german", lang: "de", expected: "Hallo, Alice!"},
+		{name: "french", lang: "fr", expected: "Bonjour, Alice !"},
+		{name: "spanish", lang: "es", expected: "¡Hola, Alice!"},
+		{name: "upper case code", lang: "DE", expected: "Hallo, Alice!"},
+		{name: "unknown falls back to english", lang: "xx", expected: "Hello, Alice!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FooGreet(FooConfig{Lang: tt.lang}, "Alice")
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestFooGreet_LanguageEmptyName(t *testing.T) {
+	result := FooGreet(FooConfig{Lang: "de"}, " ")
+	assert.Equal(t, "Hallo, World!", result)
+}
+
+func TestFooGreetAt(t *testing.T) {
+	config := FooConfig{Prefix: "✨ ", Suffix: " ✨"}
+
+	tests := []struct {
+		name     string
+		hour     int
+		minute   int
+		expected string
+	}{
+		{name: "just before morning", hour: 5, minute: 59, expected: "✨ Good night, Alice! ✨"},
+		{name: "morning starts", hour: 6, minute: 0, expected: "✨ Good morning, Alice! ✨"},
+		{name: "end of morning", hour: 11, minute: 59, expected: "✨ Good morning, Alice! ✨"},
+		{name: "afternoon starts", hour: 12, minute: 0, expected: "✨ Good afternoon, Alice! ✨"},
+		{name: "end of afternoon", hour: 17, minute: 59, expected: "✨ Good afternoon, Alice! ✨"},
+		{name: "evening starts", hour: 18, minute: 0, expected: "✨ Good evening, Alice! ✨"},
+		{name: "end of evening", hour: 21, minute: 59, expected: "✨ Good evening, Alice! ✨"},
+		{name: "night starts", hour: 22, minute: 0, expected: "✨ Good night, Alice! ✨"},
+		{name: "midnight", hour: 0, minute: 0, expected: "✨ Good night, Alice! ✨"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at := time.Date(2023, 9, 24, tt.hour, tt.minute, 0, 0, time.UTC)
+			assert.Equal(t, tt.expected, FooGreetAt(config, "Alice", at))
+		})
+	}
+}
+
+func TestFooGreetAt_EmptyName(t *testing.T) {
+	at := time.Date(2023, 9, 24, 9, 0, 0, 0, time.UTC)
+	assert.Equal(t, "Good morning, World!", FooGreetAt(FooConfig{}, "", at))
+}
+
+func TestFooGreetTemplate(t *testing.T) {
+	t.Run("custom template", func(t *testing.T) {
+		config := FooConfig{Prefix: "[", Suffix: "]", Template: "Moin {{.Name}}, welcome aboard!"}
+		greeting, err := FooGreetTemplate(config, "Alice")
+		assert.NoError(t, err)
+		assert.Equal(t, "[Moin Alice, welcome aboard!]", greeting)
+		assert.Equal(t, greeting, FooGreet(config, "Alice"))
+	})
+
+	t.Run("empty template falls back to default", func(t *testing.T) {
+		config := FooConfig{Prefix: "✨ ", Suffix: " ✨"}
+		greeting, err := FooGreetTemplate(config, "Alice")
+		assert.NoError(t, err)
+		assert.Equal(t, "✨ Hello, Alice! ✨", greeting)
+	})
+
+	t.Run("malformed template", func(t *testing.T) {
+		config := FooConfig{Template: "Hi {{.Name"}
+		greeting, err := FooGreetTemplate(config, "Alice")
+		assert.Error(t, err)
+		assert.Empty(t, greeting)
+		// FooGreet cannot report the error and uses the default greeting instead.
+		assert.Equal(t, "Hello, Alice!", FooGreet(config, "Alice"))
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := FooGreetTemplate(FooConfig{Template: "Hi {{.Nickname}}"}, "Alice")
+		assert.Error(t, err)
+	})
+}
+
 func TestFooProcessor(t *testing.T) {
 	config := FooConfig{Prefix: "[", Suffix: "]"}
 	processor := NewFooProcessor(config)
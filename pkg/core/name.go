@@ -0,0 +1,49 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// defaultName is greeted when a name is empty after sanitization.
+const defaultName = "World"
+
+// DefaultMaxNameLength is the longest name, in runes, greeted unless configured otherwise.
+const DefaultMaxNameLength = 100
+
+// ErrNameTooLong is returned by ValidateName for names above the length limit.
+var ErrNameTooLong = errors.New("name too long")
+
+// SanitizeName strips control characters (newlines, tabs, escape sequences, ...)
+// from name and trims surrounding whitespace, so user input cannot break the
+// greeting layout. Empty or all-whitespace names become "World".
+func SanitizeName(name string) string {
+	cleaned := strings.TrimSpace(StripControlCharacters(name))
+	if cleaned == "" {
+		return defaultName
+	}
+	return cleaned
+}
+
+// StripControlCharacters removes control characters (newlines, tabs, escape
+// sequences, ...) from s and leaves everything else untouched.
+func StripControlCharacters(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// ValidateName reports ErrNameTooLong when name, after SanitizeName, has more
+// than maxRunes runes.
+func ValidateName(name string, maxRunes int) error {
+	if utf8.RuneCountInString(SanitizeName(name)) > maxRunes {
+		return fmt.Errorf("%w (max %d characters)", ErrNameTooLong, maxRunes)
+	}
+	return nil
+}
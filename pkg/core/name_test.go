@@ -0,0 +1,63 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "plain name", input: "Alice", expected: "Alice"},
+		{name: "newline", input: "Ali\nce", expected: "Alice"},
+		{name: "escape sequence", input: "\x1b[31mAlice", expected: "[31mAlice"},
+		{name: "leading and trailing spaces", input: "  Alice  ", expected: "Alice"},
+		{name: "inner spaces kept", input: "Mary Ann", expected: "Mary Ann"},
+		{name: "empty string", input: "", expected: "World"},
+		{name: "only whitespace and control characters", input: " \t\r\n ", expected: "World"},
+		{name: "unicode letters kept", input: "Jörg", expected: "Jörg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SanitizeName(tt.input))
+		})
+	}
+}
+
+func TestFooGreet_SanitizesName(t *testing.T) {
+	config := FooConfig{Prefix: "✨ ", Suffix: " ✨"}
+	assert.Equal(t, "✨ Hello, Alice! ✨", FooGreet(config, " Ali\nce\t"))
+	assert.Equal(t, "✨ Hello, World! ✨", FooGreet(config, "\n"))
+}
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "short name", input: "Alice", wantErr: false},
+		{name: "at the limit", input: strings.Repeat("a", 10), wantErr: false},
+		{name: "multi-byte runes at the limit", input: strings.Repeat("ö", 10), wantErr: false},
+		{name: "over the limit", input: strings.Repeat("a", 11), wantErr: true},
+		{name: "control characters are not counted", input: strings.Repeat("a", 10) + "\n\x1b", wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateName(tt.input, 10)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrNameTooLong)
+				assert.EqualError(t, err, "name too long (max 10 characters)")
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -16,4 +16,14 @@ type RssHeadline struct {
 	Link        string `json:"link"`
 	PublishedAt string `json:"publishedAt"`
 	Source      string `json:"source"`
+	// Description is the item's summary text, if the feed provides one.
+	Description string `json:"description,omitempty"`
+	// Categories holds the item's <category> values; empty, never null, when the feed has none.
+	Categories []string `json:"categories"`
+	// ImageURL is the item's thumbnail or image; empty when the feed provides none.
+	ImageURL string `json:"imageUrl"`
+	// RawLink keeps the feed's original link when Link has been canonicalized.
+	RawLink string `json:"rawLink,omitempty"`
+	// Raw holds the original <item> XML for clients needing unmodelled fields; only set on request.
+	Raw string `json:"raw,omitempty"`
 }